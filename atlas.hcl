@@ -0,0 +1,27 @@
+# twitch module owns only its own tables, shared tables and the schemas are
+# migrated by arnobot-shared, so revisions are kept apart from shared ones.
+env "local" {
+  src = "file://db/schemas"
+
+  url = getenv("DB_DSN")
+
+  dev = getenv("DB_DSN_DEV")
+
+  migration {
+    dir              = "file://db/migrations"
+    revisions_schema = "twitch_revisions"
+  }
+}
+
+env "staging" {
+  src = "file://db/schemas"
+
+  url = getenv("DB_DSN_STAGING")
+
+  dev = getenv("DB_DSN_DEV")
+
+  migration {
+    dir              = "file://db/migrations"
+    revisions_schema = "twitch_revisions"
+  }
+}
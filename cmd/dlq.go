@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/arnokay/arnobot-shared/trace"
+
+	"github.com/arnokay/arnobot-twitch/internal/data"
+	"github.com/arnokay/arnobot-twitch/internal/service"
+)
+
+const dlqUsage = `usage: main [flags] dlq <command>
+
+commands:
+  list [-kind eventsub|chat-send] [-limit n] [-after seq]
+  show <seq>
+  replay <seq>...
+  purge [-kind eventsub|chat-send] [-all] [<seq>...]`
+
+// runDLQ inspects and replays dead letters directly through jetstream, so it
+// works even when service is down.
+func runDLQ(args []string) error {
+	if len(args) == 0 {
+		return errors.New(dlqUsage)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	ctx = trace.Context(ctx, trace.New())
+
+	nc, js, _ := openMB(ctx)
+	defer nc.Drain()
+
+	deadLetterService := service.NewDeadLetterService(ctx, nc, js)
+
+	switch args[0] {
+	case "list":
+		return dlqList(ctx, deadLetterService, args[1:])
+	case "show":
+		return dlqShow(ctx, deadLetterService, args[1:])
+	case "replay":
+		return dlqReplay(ctx, deadLetterService, args[1:])
+	case "purge":
+		return dlqPurge(ctx, deadLetterService, args[1:])
+	}
+
+	return errors.New(dlqUsage)
+}
+
+func dlqList(ctx context.Context, s *service.DeadLetterService, args []string) error {
+	var arg data.DeadLetterList
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	kind := fs.String("kind", "", "eventsub or chat-send")
+	fs.IntVar(&arg.Limit, "limit", 0, "max number of dead letters")
+	fs.Uint64Var(&arg.After, "after", 0, "list dead letters after this sequence")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+	arg.Kind = data.DeadLetterKind(*kind)
+
+	deadLetters, err := s.List(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SEQ\tKIND\tATTEMPTS\tFAILED AT\tTYPE\tERROR")
+	for _, d := range deadLetters {
+		typ := d.SubscriptionType
+		if typ == "" {
+			typ = d.Subject
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%s\n", d.Seq, d.Kind, d.Attempts, d.FailedAt.Format(time.RFC3339), typ, d.Error)
+	}
+
+	return w.Flush()
+}
+
+func dlqShow(ctx context.Context, s *service.DeadLetterService, args []string) error {
+	if len(args) != 1 {
+		return errors.New(dlqUsage)
+	}
+	seq, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid sequence %q", args[0])
+	}
+
+	deadLetter, err := s.Get(ctx, data.DeadLetterGet{Seq: seq})
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(deadLetter)
+}
+
+func dlqReplay(ctx context.Context, s *service.DeadLetterService, args []string) error {
+	if len(args) == 0 {
+		return errors.New(dlqUsage)
+	}
+
+	for _, a := range args {
+		seq, err := strconv.ParseUint(a, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid sequence %q", a)
+		}
+
+		_, err = s.Replay(ctx, data.DeadLetterReplay{Seq: seq})
+		if err != nil {
+			return fmt.Errorf("cannot replay %d: %w", seq, err)
+		}
+		fmt.Printf("replayed %d\n", seq)
+	}
+
+	return nil
+}
+
+func dlqPurge(ctx context.Context, s *service.DeadLetterService, args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	kind := fs.String("kind", "", "eventsub or chat-send")
+	all := fs.Bool("all", false, "purge every dead letter")
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		if *kind == "" && !*all {
+			return errors.New("purge needs sequences, -kind or -all")
+		}
+		return s.Purge(ctx, data.DeadLetterPurge{Kind: data.DeadLetterKind(*kind), All: *all})
+	}
+
+	for _, a := range fs.Args() {
+		seq, err := strconv.ParseUint(a, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid sequence %q", a)
+		}
+
+		err = s.Purge(ctx, data.DeadLetterPurge{Seq: seq})
+		if err != nil {
+			return fmt.Errorf("cannot purge %d: %w", seq, err)
+		}
+	}
+
+	return nil
+}
@@ -2,7 +2,8 @@ package main
 
 import (
 	"context"
-	
+	"flag"
+	"fmt"
 	"os"
 	"time"
 
@@ -55,13 +56,22 @@ func main() {
   applog.SetDefault(logger)
 	app.logger = logger
 
+	if flag.Arg(0) == "dlq" {
+		err := runDLQ(flag.Args()[1:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// load db
 	dbConn := openDB()
 	app.db = dbConn
 	app.storage = storage.NewStorage(app.db)
 
 	// load message broker
-	mbConn, _, kv := openMB(ctx)
+	mbConn, js, kv := openMB(ctx)
 	app.msgBroker = mbConn
 	app.cache = kv
 
@@ -69,15 +79,26 @@ func main() {
 	services := &service.Services{}
 	services.TransactionService = sharedService.NewPgxTransactionService(app.db)
 	services.AuthModule = sharedService.NewAuthModule(app.msgBroker)
-	services.PlatformModule = sharedService.NewPlatformModuleOut(app.msgBroker)
+	services.PlatformModule = service.NewPlatformModuleOut(app.msgBroker)
 	services.HelixManager = service.NewHelixManager(
 		app.cache,
 		services.AuthModule,
 		config.Config.Twitch.ClientID,
 		config.Config.Twitch.ClientSecret,
 	)
-	services.TwitchService = service.NewTwitchService(services.HelixManager)
-	services.WebhookService = service.NewWebhookService(services.HelixManager, services.TwitchService)
+	services.TwitchService = service.NewTwitchService(services.HelixManager, services.AuthModule, app.cache)
+	services.ModerationService = service.NewModerationService(app.storage)
+	services.SharedChatService = service.NewSharedChatService(app.cache)
+	services.DeadLetterService = service.NewDeadLetterService(ctx, app.msgBroker, js)
+	services.IngestService = service.NewIngestService(ctx, js, services.DeadLetterService)
+	if config.Config.EventSub.Transport == config.EventSubTransportWebsocket {
+		services.EventSubWebsocket = service.NewEventSubWebsocketService()
+	}
+	services.WebhookService = service.NewWebhookService(
+		services.HelixManager,
+		services.TwitchService,
+		services.EventSubWebsocket,
+	)
 	services.BotService = service.NewBotService(
 		app.storage,
 		services.TransactionService,
@@ -89,6 +110,7 @@ func main() {
 
 	// load api middlewares
 	app.apiMiddlewares = apiMiddleware.New(
+		app.cache,
 		sharedMiddleware.NewAuthMiddleware(app.services.AuthModule),
 	)
 
@@ -96,15 +118,26 @@ func main() {
 	app.apiControllers = &apiController.Contollers{
 		WebhookController: apiController.NewWebhookController(
 			app.apiMiddlewares,
+			app.services.TwitchService,
 			app.services.BotService,
+			app.services.ModerationService,
+			app.services.SharedChatService,
+			app.services.IngestService,
 			app.services.PlatformModule,
 		),
 	}
 
 	// load mb controllers
 	app.mbControllers = &mbController.Controllers{
-		ChatController: mbController.NewChatController(app.services.TwitchService),
-		BotController:  mbController.NewBotController(app.services.BotService),
+		ChatController:       mbController.NewChatController(app.services.TwitchService, app.services.DeadLetterService),
+		BotController:        mbController.NewBotController(app.services.BotService),
+		ChannelController:    mbController.NewChannelController(app.services.TwitchService, app.services.BotService),
+		RewardController:     mbController.NewRewardController(app.services.TwitchService),
+		PollController:       mbController.NewPollController(app.services.TwitchService),
+		PredictionController: mbController.NewPredictionController(app.services.TwitchService),
+		AdController:         mbController.NewAdController(app.services.TwitchService),
+		AutomodController:    mbController.NewAutomodController(app.services.TwitchService, app.services.BotService),
+		DeadLetterController: mbController.NewDeadLetterController(app.services.DeadLetterService),
 	}
 
 	app.Start()
@@ -142,6 +175,8 @@ func openMB(ctx context.Context) (*nats.Conn, jetstream.JetStream, jetstream.Key
 	assert.NoError(err, "openMB: cannot open jetstream")
 	kv, err := js.CreateOrUpdateKeyValue(ctx, jetstream.KeyValueConfig{
 		Bucket: "default-twitch",
+		// enables per-key TTL
+		LimitMarkerTTL: time.Minute,
 	})
 	assert.NoError(err, "openMB: cannot create KVstore")
 
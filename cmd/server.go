@@ -48,6 +48,22 @@ func (app *application) Start() {
 			startError <- err
 		}
 	}()
+
+	go func() {
+		err := startIngestWorkers(app)
+		if err != nil {
+			startError <- err
+		}
+	}()
+
+	if app.services.EventSubWebsocket != nil {
+		go func() {
+			err := startEventSubWebsocket(app)
+			if err != nil {
+				startError <- err
+			}
+		}()
+	}
 	select {
 	case err := <-startError:
 		app.logger.Error("application start error", "err", err)
@@ -70,6 +86,17 @@ func (app *application) Shutdown(ctx context.Context) error {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
+		if app.services.EventSubWebsocket != nil {
+			app.logger.Debug("#shutdown.eventsub: closing eventsub websocket")
+			app.services.EventSubWebsocket.Stop(ctx)
+			app.logger.Debug("#shutdown.eventsub: closed eventsub websocket")
+		}
+
+		// workers need mb connection to ack handled notifications
+		app.logger.Debug("#shutdown.ingest: stopping ingest workers")
+		app.services.IngestService.Stop(ctx)
+		app.logger.Debug("#shutdown.ingest: stopped ingest workers")
+
 		app.logger.Debug("#shutdown.mb: gracefully closing mb connection")
 
 		done := make(chan struct{})
@@ -131,6 +158,35 @@ func startMBServer(a *application) error {
 	return nil
 }
 
+func startIngestWorkers(a *application) error {
+	err := a.services.IngestService.Start(a.apiControllers.WebhookController.Dispatch)
+	if err != nil {
+		return fmt.Errorf("startIngestWorkers: %w", err)
+	}
+
+	return nil
+}
+
+// startEventSubWebsocket feeds websocket notifications to the same ingest
+// stream as webhook callback. It refuses to start when enabled bots don't fit
+// into single session.
+func startEventSubWebsocket(a *application) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := a.services.BotService.WebsocketCheckCapacity(ctx, "")
+	if err != nil {
+		return fmt.Errorf("startEventSubWebsocket: %w", err)
+	}
+
+	a.services.EventSubWebsocket.Start(
+		a.services.IngestService.Enqueue,
+		a.services.BotService.SelectedBotsResubscribe,
+	)
+
+	return nil
+}
+
 func startAPIServer(a *application) error {
 	e := echo.New()
 
@@ -0,0 +1,18 @@
+-- name: TwitchModerationActionCreate :execrows
+INSERT INTO twitch.moderation_actions (
+    eventsub_message_id,
+    broadcaster_id,
+    source_broadcaster_id,
+    moderator_id,
+    moderator_login,
+    action,
+    target_id,
+    target_login,
+    reason,
+    expires_at,
+    message_id,
+    message_body,
+    details)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+ON CONFLICT (eventsub_message_id)
+    DO NOTHING;
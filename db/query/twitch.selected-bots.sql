@@ -0,0 +1,7 @@
+-- name: TwitchSelectedBotsEnabledGet :many
+SELECT
+    *
+FROM
+    twitch.selected_bots
+WHERE
+    enabled;
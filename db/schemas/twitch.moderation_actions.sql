@@ -0,0 +1,26 @@
+-- moderation audit trail written by the twitch module from channel.moderate
+-- events. Schema is owned by shared schemas, table is migrated from
+-- db/migrations, see atlas.hcl.
+CREATE SCHEMA IF NOT EXISTS twitch;
+
+CREATE TABLE "twitch"."moderation_actions" (
+  "id" bigserial NOT NULL,
+  "eventsub_message_id" character varying(100) NOT NULL,
+  "broadcaster_id" character varying(100) NOT NULL,
+  "source_broadcaster_id" character varying(100) NULL,
+  "moderator_id" character varying(100) NOT NULL,
+  "moderator_login" character varying(100) NOT NULL,
+  "action" character varying(50) NOT NULL,
+  "target_id" character varying(100) NULL,
+  "target_login" character varying(100) NULL,
+  "reason" text NULL,
+  "expires_at" timestamp NULL,
+  "message_id" character varying(100) NULL,
+  "message_body" text NULL,
+  "details" jsonb NULL,
+  "created_at" timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "moderation_actions_eventsub_message_id_key" UNIQUE ("eventsub_message_id")
+);
+CREATE INDEX "moderation_actions_broadcaster_id_created_at_idx" ON "twitch"."moderation_actions" ("broadcaster_id", "created_at");
+CREATE INDEX "moderation_actions_broadcaster_id_target_id_idx" ON "twitch"."moderation_actions" ("broadcaster_id", "target_id");
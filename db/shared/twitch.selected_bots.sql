@@ -0,0 +1,10 @@
+-- copy of the table owned and migrated by arnobot-shared, it is only here so
+-- sqlc knows its columns. Keep it in sync with shared schemas, atlas doesn't
+-- read this directory.
+CREATE TABLE twitch.selected_bots (
+    user_id uuid NOT NULL PRIMARY KEY,
+    broadcaster_id varchar(100) NOT NULL,
+    bot_id varchar(100) NOT NULL,
+    enabled bool NOT NULL DEFAULT FALSE,
+    updated_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -4,10 +4,11 @@ go 1.24.4
 
 require (
 	github.com/arnokay/arnobot-shared v0.1.1-0.20250708203729-81662fe62b75
+	github.com/coder/websocket v1.8.13
 	github.com/google/uuid v1.6.0
 	github.com/jackc/pgx/v5 v5.7.4
 	github.com/labstack/echo/v4 v4.13.3
-	github.com/nats-io/nats.go v1.41.2
+	github.com/nats-io/nats.go v1.42.0
 	github.com/nicklaw5/helix/v2 v2.31.1
 )
 
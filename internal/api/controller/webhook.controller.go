@@ -1,19 +1,23 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
-	
+	"io"
+	"net/http"
 	"strings"
 
+	"github.com/arnokay/arnobot-shared/apperror"
 	"github.com/arnokay/arnobot-shared/applog"
-	"github.com/arnokay/arnobot-shared/events"
+	sharedEvents "github.com/arnokay/arnobot-shared/events"
 	"github.com/arnokay/arnobot-shared/platform"
-	sharedService "github.com/arnokay/arnobot-shared/service"
 	"github.com/labstack/echo/v4"
 	"github.com/nicklaw5/helix/v2"
 
 	"github.com/arnokay/arnobot-twitch/internal/api/middleware"
+	"github.com/arnokay/arnobot-twitch/internal/config"
 	"github.com/arnokay/arnobot-twitch/internal/data"
+	"github.com/arnokay/arnobot-twitch/internal/events"
 	"github.com/arnokay/arnobot-twitch/internal/service"
 )
 
@@ -22,24 +26,39 @@ type WebhookController struct {
 
 	middlewares *middleware.Middlewares
 
-	twitchService  *service.TwitchService
-	botService     *service.BotService
-	platformModule *sharedService.PlatformModuleOut
+	twitchService     *service.TwitchService
+	botService        *service.BotService
+	moderationService *service.ModerationService
+	sharedChatService *service.SharedChatService
+	ingestService     *service.IngestService
+	platformModule    *service.PlatformModuleOut
+
+	suppressSharedChat bool
 }
 
 func NewWebhookController(
 	middlewares *middleware.Middlewares,
+	twitchService *service.TwitchService,
 	botService *service.BotService,
-	platformModule *sharedService.PlatformModuleOut,
+	moderationService *service.ModerationService,
+	sharedChatService *service.SharedChatService,
+	ingestService *service.IngestService,
+	platformModule *service.PlatformModuleOut,
 ) *WebhookController {
 	logger := applog.NewServiceLogger("ChatController")
 
 	return &WebhookController{
 		logger: logger,
 
-		middlewares:    middlewares,
-		botService:     botService,
-		platformModule: platformModule,
+		middlewares:       middlewares,
+		twitchService:     twitchService,
+		botService:        botService,
+		moderationService: moderationService,
+		sharedChatService: sharedChatService,
+		ingestService:     ingestService,
+		platformModule:    platformModule,
+
+		suppressSharedChat: config.Config.Twitch.SuppressSharedChat,
 	}
 }
 
@@ -48,35 +67,174 @@ func (c *WebhookController) Routes(parentGroup *echo.Group) {
 }
 
 func (c *WebhookController) Callback(ctx echo.Context) error {
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		c.logger.ErrorContext(ctx.Request().Context(), "cannot read body", "err", err)
+		return apperror.ErrInvalidInput
+	}
+
+	// notification is handled by ingest workers, twitch only needs to know
+	// that it is stored
+	err = c.ingestService.Enqueue(ctx.Request().Context(), data.EventSubNotification{
+		MessageID:        ctx.Request().Header.Get("Twitch-Eventsub-Message-Id"),
+		MessageType:      ctx.Request().Header.Get("Twitch-Eventsub-Message-Type"),
+		SubscriptionType: ctx.Request().Header.Get("Twitch-Eventsub-Subscription-Type"),
+		Body:             body,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// Dispatch handles stored notification, returned error means it should be
+// retried.
+func (c *WebhookController) Dispatch(ctx context.Context, msg data.EventSubNotification) error {
 	var rawEvent struct {
 		Subscription helix.EventSubSubscription `json:"subscription"`
 		Event        json.RawMessage            `json:"event"`
 	}
-	err := ctx.Bind(&rawEvent)
+	err := json.Unmarshal(msg.Body, &rawEvent)
 	if err != nil {
-		c.logger.ErrorContext(ctx.Request().Context(), "cannot parse body", "event", "channel.chat.message", "err", err)
-		return nil
+		c.logger.ErrorContext(ctx, "cannot parse body", "err", err, "messageID", msg.MessageID)
+		// retrying will not fix the body
+		return apperror.New(apperror.CodeInvalidInput, "cannot parse eventsub body", err)
+	}
+
+	if msg.MessageType == "revocation" {
+		return c.revocation(ctx, rawEvent.Subscription)
 	}
 
-	switch ctx.Request().Header.Get("Twitch-Eventsub-Subscription-Type") {
+	switch msg.SubscriptionType {
 	case helix.EventSubTypeChannelChatMessage:
-		var event helix.EventSubChannelChatMessageEvent
-		json.Unmarshal(rawEvent.Event, &event)
+		return c.chatMessage(ctx, rawEvent.Event)
+	case helix.EventSubTypeChannelChatNotification:
+		return c.chatNotification(ctx, rawEvent.Event)
+	case helix.EventSubTypeChannelChatMessageDelete:
+		return c.chatMessageDelete(ctx, rawEvent.Event)
+	case helix.EventSubTypeChannelChatClear:
+		return c.chatClear(ctx, rawEvent.Event)
+	case helix.EventSubTypeChannelChatClearUserMessages:
+		return c.chatClearUserMessages(ctx, rawEvent.Event)
+	case helix.EventSubTypeStreamOnline:
+		return c.streamOnline(ctx, rawEvent.Event)
+	case helix.EventSubTypeStreamOffline:
+		return c.streamOffline(ctx, rawEvent.Event)
+	case helix.EventSubTypeChannelUpdate:
+		return c.channelUpdate(ctx, rawEvent.Event)
+	case helix.EventSubTypeChannelFollow:
+		return c.channelFollow(ctx, rawEvent.Event)
+	case helix.EventSubTypeChannelSubscription:
+		return c.channelSubscribe(ctx, rawEvent.Event)
+	case helix.EventSubTypeChannelSubscriptionMessage:
+		return c.channelSubscriptionMessage(ctx, rawEvent.Event)
+	case helix.EventSubTypeChannelSubscriptionGift:
+		return c.channelSubscriptionGift(ctx, rawEvent.Event)
+	case helix.EventSubTypeChannelSubscriptionEnd:
+		return c.channelSubscriptionEnd(ctx, rawEvent.Event)
+	case helix.EventSubTypeChannelCheer:
+		return c.channelCheer(ctx, rawEvent.Event)
+	case helix.EventSubTypeChannelRaid:
+		return c.channelRaid(ctx, rawEvent.Subscription, rawEvent.Event)
+	case helix.EventSubTypeChannelPointsCustomRewardRedemptionAdd:
+		return c.channelPointsRedemption(ctx, rawEvent.Event, c.platformModule.RedemptionAddNotify)
+	case helix.EventSubTypeChannelPointsCustomRewardRedemptionUpdate:
+		return c.channelPointsRedemption(ctx, rawEvent.Event, c.platformModule.RedemptionUpdateNotify)
+	case helix.EventSubTypeChannelPollBegin:
+		return c.channelPoll(ctx, rawEvent.Event, c.platformModule.PollBeginNotify)
+	case helix.EventSubTypeChannelPollProgress:
+		return c.channelPoll(ctx, rawEvent.Event, c.platformModule.PollProgressNotify)
+	case helix.EventSubTypeChannelPollEnd:
+		return c.channelPoll(ctx, rawEvent.Event, c.platformModule.PollEndNotify)
+	case helix.EventSubTypeChannelPredictionBegin:
+		return c.channelPrediction(ctx, rawEvent.Event, c.platformModule.PredictionBeginNotify)
+	case helix.EventSubTypeChannelPredictionProgress:
+		return c.channelPrediction(ctx, rawEvent.Event, c.platformModule.PredictionProgressNotify)
+	case helix.EventSubTypeChannelPredictionLock:
+		return c.channelPrediction(ctx, rawEvent.Event, c.platformModule.PredictionLockNotify)
+	case helix.EventSubTypeChannelPredictionEnd:
+		return c.channelPrediction(ctx, rawEvent.Event, c.platformModule.PredictionEndNotify)
+	case helix.EventSubTypeHypeTrainBegin:
+		return c.hypeTrain(ctx, rawEvent.Event, c.platformModule.HypeTrainBeginNotify)
+	case helix.EventSubTypeHypeTrainProgress:
+		return c.hypeTrain(ctx, rawEvent.Event, c.platformModule.HypeTrainProgressNotify)
+	case helix.EventSubTypeHypeTrainEnd:
+		return c.hypeTrain(ctx, rawEvent.Event, c.platformModule.HypeTrainEndNotify)
+	case "channel.ad_break.begin":
+		return c.channelAdBreakBegin(ctx, rawEvent.Event)
+	case "channel.shared_chat.begin":
+		return c.sharedChat(ctx, rawEvent.Event, c.platformModule.SharedChatBeginNotify)
+	case "channel.shared_chat.update":
+		return c.sharedChat(ctx, rawEvent.Event, c.platformModule.SharedChatUpdateNotify)
+	case "channel.shared_chat.end":
+		return c.sharedChat(ctx, rawEvent.Event, c.platformModule.SharedChatEndNotify)
+	case "channel.moderate":
+		return c.channelModerate(ctx, msg.MessageID, rawEvent.Event)
+	case "automod.message.hold":
+		return c.automodMessage(ctx, rawEvent.Event, c.platformModule.AutomodMessageHoldNotify)
+	case "automod.message.update":
+		return c.automodMessage(ctx, rawEvent.Event, c.platformModule.AutomodMessageUpdateNotify)
+	}
 
-		bot, err := c.botService.SelectedBotGetByBroadcasterID(ctx.Request().Context(), event.BroadcasterUserID)
-		if err != nil {
-			c.logger.ErrorContext(ctx.Request().Context(), "cannot get selected bot")
-			return nil
-		}
+	return nil
+}
+
+// decodeEvent parses event of the notification, retrying will not fix the
+// event, so error is invalid input.
+func decodeEvent(raw json.RawMessage, event any) error {
+	err := json.Unmarshal(raw, event)
+	if err != nil {
+		return apperror.New(apperror.CodeInvalidInput, "cannot parse eventsub event", err)
+	}
+
+	return nil
+}
 
-		internalEvent := events.Message{
-			EventCommon: events.EventCommon{
-				Platform:      platform.Twitch,
-				UserID:        bot.UserID,
-				BroadcasterID: event.BroadcasterUserID,
-				BotID:         bot.BotID,
-			},
-			MessageID: event.MessageID,
+func (c *WebhookController) eventCommon(ctx context.Context, broadcasterID string) (sharedEvents.EventCommon, error) {
+	bot, err := c.botService.SelectedBotGetByBroadcasterID(ctx, broadcasterID)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot get selected bot", "broadcasterID", broadcasterID)
+		return sharedEvents.EventCommon{}, err
+	}
+
+	return sharedEvents.EventCommon{
+		Platform:      platform.Twitch,
+		UserID:        bot.UserID,
+		BroadcasterID: broadcasterID,
+		BotID:         bot.BotID,
+	}, nil
+}
+
+func (c *WebhookController) chatMessage(ctx context.Context, raw json.RawMessage) error {
+	// helix has no shared chat fields
+	var event struct {
+		helix.EventSubChannelChatMessageEvent
+		SourceBroadcasterUserID    string `json:"source_broadcaster_user_id"`
+		SourceBroadcasterUserLogin string `json:"source_broadcaster_user_login"`
+		SourceBroadcasterUserName  string `json:"source_broadcaster_user_name"`
+		SourceMessageID            string `json:"source_message_id"`
+	}
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	session, inSession := c.sharedChatSession(ctx, event.BroadcasterUserID, event.SourceBroadcasterUserID)
+	if c.suppressShared(event.BroadcasterUserID, event.SourceBroadcasterUserID, session, inSession) {
+		c.logger.DebugContext(ctx, "shared chat message is suppressed", "broadcasterID", event.BroadcasterUserID, "sourceBroadcasterID", event.SourceBroadcasterUserID)
+		return nil
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	internalEvent := events.Message{
+		Message: sharedEvents.Message{
+			EventCommon: common,
+			MessageID:   event.MessageID,
 			// weird \U000e0000 appears in every second message
 			Message:          strings.Replace(event.Message.Text, "\U000e0000", "", 1),
 			ReplyTo:          event.Reply.ParentMessageID,
@@ -86,14 +244,977 @@ func (c *WebhookController) Callback(ctx echo.Context) error {
 			ChatterName:      event.ChatterUserName,
 			ChatterLogin:     event.ChatterUserLogin,
 			ChatterRole:      data.GetChatterRole(event.Badges),
+		},
+		Fragments:   data.NewMessageFragmentsFromEventSub(event.Message.Fragments),
+		Badges:      data.NewBadgesFromEventSub(event.Badges),
+		Color:       event.Color,
+		MessageType: events.MessageType(event.MessageType),
+		RewardID:    event.ChannelPointsCustomRewardID,
+		Bits:        int(event.Cheer.Bits),
+
+		SourceBroadcasterID:    event.SourceBroadcasterUserID,
+		SourceBroadcasterLogin: event.SourceBroadcasterUserLogin,
+		SourceBroadcasterName:  event.SourceBroadcasterUserName,
+		SourceMessageID:        event.SourceMessageID,
+	}
+	if event.Reply.ParentMessageID != "" {
+		internalEvent.Reply = &events.MessageReply{
+			ParentMessageID:   event.Reply.ParentMessageID,
+			ParentMessageBody: event.Reply.ParentMessageBody,
+			ParentUserID:      event.Reply.ParentUserID,
+			ParentUserLogin:   event.Reply.ParentUserLogin,
+			ParentUserName:    event.Reply.ParentUserName,
+			ThreadMessageID:   event.Reply.ThreadMessageID,
+			ThreadUserID:      event.Reply.ThreadUserID,
+			ThreadUserLogin:   event.Reply.ThreadUserLogin,
+			ThreadUserName:    event.Reply.ThreadUserName,
 		}
+	}
+	if inSession {
+		internalEvent.SharedChatSessionID = session.ID
+	}
+
+	err = c.platformModule.ChatMessageNotify(ctx, internalEvent)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send message to core")
+		return err
+	}
+
+	return nil
+}
+
+// sharedChatSession returns tracked shared chat session of the broadcaster
+// for event that came through shared chat.
+func (c *WebhookController) sharedChatSession(
+	ctx context.Context,
+	broadcasterID string,
+	sourceBroadcasterID string,
+) (data.SharedChatSession, bool) {
+	if sourceBroadcasterID == "" {
+		return data.SharedChatSession{}, false
+	}
+
+	session, err := c.sharedChatService.SessionGet(ctx, broadcasterID)
+	if err != nil {
+		return data.SharedChatSession{}, false
+	}
+
+	return session, true
+}
+
+// suppressShared reports whether event from other channel of shared chat is
+// dropped. Source has to be a participant of the tracked session, event is
+// forwarded when session is not tracked, so it is not lost.
+func (c *WebhookController) suppressShared(
+	broadcasterID string,
+	sourceBroadcasterID string,
+	session data.SharedChatSession,
+	inSession bool,
+) bool {
+	return c.suppressSharedChat &&
+		inSession &&
+		data.IsFromOtherChannel(broadcasterID, sourceBroadcasterID) &&
+		session.HasParticipant(sourceBroadcasterID)
+}
+
+func (c *WebhookController) chatNotification(ctx context.Context, raw json.RawMessage) error {
+	var event data.ChatNotificationEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	session, inSession := c.sharedChatSession(ctx, event.BroadcasterUserID, event.SourceBroadcasterUserID)
+	if c.suppressShared(event.BroadcasterUserID, event.SourceBroadcasterUserID, session, inSession) {
+		c.logger.DebugContext(ctx, "shared chat notification is suppressed", "broadcasterID", event.BroadcasterUserID, "sourceBroadcasterID", event.SourceBroadcasterUserID)
+		return nil
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	internalEvent := data.NewChatNotificationFromEventSub(event)
+	internalEvent.EventCommon = common
+
+	err = c.platformModule.ChatNotificationNotify(ctx, internalEvent)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send chat notification to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) chatMessageDelete(ctx context.Context, raw json.RawMessage) error {
+	var event helix.EventSubChannelChatMessageDeleteEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = c.platformModule.MessageDeleteNotify(ctx, events.MessageDelete{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		MessageID:        event.MessageID,
+		ChatterID:        event.TargetUserID,
+		ChatterLogin:     event.TargetUserLogin,
+		ChatterName:      event.TargetUserName,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send message delete to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) chatClear(ctx context.Context, raw json.RawMessage) error {
+	var event helix.EventSubChannelChatClearEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = c.platformModule.ChatClearNotify(ctx, events.ChatClear{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send chat clear to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) chatClearUserMessages(ctx context.Context, raw json.RawMessage) error {
+	var event helix.EventSubChannelChatClearUserMessagesEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = c.platformModule.ChatClearUserMessagesNotify(ctx, events.ChatClearUserMessages{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		ChatterID:        event.TargetUserID,
+		ChatterLogin:     event.TargetUserLogin,
+		ChatterName:      event.TargetUserName,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send chat clear user messages to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) streamOnline(ctx context.Context, raw json.RawMessage) error {
+	var event helix.EventSubStreamOnlineEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = c.platformModule.StreamOnlineNotify(ctx, events.StreamOnline{
+		EventCommon:      common,
+		StreamID:         event.ID,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		Type:             event.Type,
+		StartedAt:        event.StartedAt.Time,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send stream online to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) streamOffline(ctx context.Context, raw json.RawMessage) error {
+	var event helix.EventSubStreamOfflineEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = c.platformModule.StreamOfflineNotify(ctx, events.StreamOffline{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send stream offline to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) channelUpdate(ctx context.Context, raw json.RawMessage) error {
+	// v2 has content classification labels instead of is_mature
+	var event struct {
+		helix.EventSubChannelUpdateEvent
+		ContentClassificationLabels []string `json:"content_classification_labels"`
+	}
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = c.platformModule.ChannelUpdateNotify(ctx, events.ChannelUpdate{
+		EventCommon:                 common,
+		BroadcasterLogin:            event.BroadcasterUserLogin,
+		BroadcasterName:             event.BroadcasterUserName,
+		Title:                       event.Title,
+		Language:                    event.Language,
+		CategoryID:                  event.CategoryID,
+		CategoryName:                event.CategoryName,
+		ContentClassificationLabels: event.ContentClassificationLabels,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send channel update to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) channelFollow(ctx context.Context, raw json.RawMessage) error {
+	var event helix.EventSubChannelFollowEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = c.platformModule.FollowNotify(ctx, events.Follow{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		FollowerID:       event.UserID,
+		FollowerLogin:    event.UserLogin,
+		FollowerName:     event.UserName,
+		FollowedAt:       event.FollowedAt.Time,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send follow to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) channelSubscribe(ctx context.Context, raw json.RawMessage) error {
+	var event helix.EventSubChannelSubscribeEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = c.platformModule.SubscribeNotify(ctx, events.Subscribe{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		SubscriberID:     event.UserID,
+		SubscriberLogin:  event.UserLogin,
+		SubscriberName:   event.UserName,
+		Tier:             event.Tier,
+		IsGift:           event.IsGift,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send subscribe to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) channelSubscriptionMessage(ctx context.Context, raw json.RawMessage) error {
+	var event helix.EventSubChannelSubscriptionMessageEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	var emotes []events.Emote
+	for _, emote := range event.Message.Emotes {
+		emotes = append(emotes, events.Emote{
+			ID:    emote.ID,
+			Begin: emote.Begin,
+			End:   emote.End,
+		})
+	}
+
+	err = c.platformModule.SubscriptionMessageNotify(ctx, events.SubscriptionMessage{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		SubscriberID:     event.UserID,
+		SubscriberLogin:  event.UserLogin,
+		SubscriberName:   event.UserName,
+		Tier:             event.Tier,
+		Message:          event.Message.Text,
+		Emotes:           emotes,
+		CumulativeMonths: event.CumulativeMonths,
+		StreakMonths:     event.StreakMonths,
+		DurationMonths:   event.DurationMonths,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send subscription message to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) channelSubscriptionGift(ctx context.Context, raw json.RawMessage) error {
+	var event helix.EventSubChannelSubscriptionGiftEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
 
-		err = c.platformModule.ChatMessageNotify(ctx.Request().Context(), internalEvent)
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = c.platformModule.SubscriptionGiftNotify(ctx, events.SubscriptionGift{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		GifterID:         event.UserID,
+		GifterLogin:      event.UserLogin,
+		GifterName:       event.UserName,
+		Tier:             event.Tier,
+		Total:            event.Total,
+		CumulativeTotal:  event.CumulativeTotal,
+		IsAnonymous:      event.IsAnonymous,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send subscription gift to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) channelSubscriptionEnd(ctx context.Context, raw json.RawMessage) error {
+	var event helix.EventSubChannelSubscribeEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = c.platformModule.SubscriptionEndNotify(ctx, events.SubscriptionEnd{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		SubscriberID:     event.UserID,
+		SubscriberLogin:  event.UserLogin,
+		SubscriberName:   event.UserName,
+		Tier:             event.Tier,
+		IsGift:           event.IsGift,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send subscription end to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) channelCheer(ctx context.Context, raw json.RawMessage) error {
+	var event helix.EventSubChannelCheerEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	// fragments are still parsed without known prefixes, they are checked
+	// against bits anyway
+	prefixes, err := c.twitchService.AppCheermotePrefixes(ctx, event.BroadcasterUserID)
+	if err != nil {
+		c.logger.WarnContext(ctx, "cannot get cheermote prefixes", "err", err, "broadcasterID", event.BroadcasterUserID)
+	}
+
+	err = c.platformModule.CheerNotify(ctx, events.Cheer{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		CheererID:        event.UserID,
+		CheererLogin:     event.UserLogin,
+		CheererName:      event.UserName,
+		IsAnonymous:      event.IsAnonymous,
+		Bits:             event.Bits,
+		Message:          event.Message,
+		Fragments:        data.ParseCheerFragments(event.Message, prefixes, event.Bits),
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send cheer to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) channelRaid(ctx context.Context, sub helix.EventSubSubscription, raw json.RawMessage) error {
+	var event helix.EventSubChannelRaidEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	// both sides of the raid can be ours, so direction is taken from the
+	// subscription and not from the event
+	direction := events.RaidOutgoing
+	broadcasterID := event.FromBroadcasterUserID
+	if sub.Condition.ToBroadcasterUserID != "" {
+		direction = events.RaidIncoming
+		broadcasterID = event.ToBroadcasterUserID
+	}
+
+	common, err := c.eventCommon(ctx, broadcasterID)
+	if err != nil {
+		return err
+	}
+
+	err = c.platformModule.RaidNotify(ctx, events.Raid{
+		EventCommon:          common,
+		Direction:            direction,
+		FromBroadcasterID:    event.FromBroadcasterUserID,
+		FromBroadcasterLogin: event.FromBroadcasterUserLogin,
+		FromBroadcasterName:  event.FromBroadcasterUserName,
+		ToBroadcasterID:      event.ToBroadcasterUserID,
+		ToBroadcasterLogin:   event.ToBroadcasterUserLogin,
+		ToBroadcasterName:    event.ToBroadcasterUserName,
+		Viewers:              event.Viewers,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send raid to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) channelPointsRedemption(
+	ctx context.Context,
+	raw json.RawMessage,
+	notify func(context.Context, events.RewardRedemption) error,
+) error {
+	var event helix.EventSubChannelPointsCustomRewardRedemptionEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = notify(ctx, events.RewardRedemption{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		RedemptionID:     event.ID,
+		RedeemerID:       event.UserID,
+		RedeemerLogin:    event.UserLogin,
+		RedeemerName:     event.UserName,
+		UserInput:        event.UserInput,
+		Status:           event.Status,
+		Reward: events.Reward{
+			ID:     event.Reward.ID,
+			Title:  event.Reward.Title,
+			Cost:   event.Reward.Cost,
+			Prompt: event.Reward.Prompt,
+		},
+		RedeemedAt: event.RedeemedAt.Time,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send reward redemption to core")
+		return err
+	}
+
+	return nil
+}
+
+// channelPoll handles begin, progress and end, end event has status and
+// ended_at instead of ends_at.
+func (c *WebhookController) channelPoll(
+	ctx context.Context,
+	raw json.RawMessage,
+	notify func(context.Context, events.Poll) error,
+) error {
+	var event struct {
+		helix.EventSubChannelPollEndEvent
+		EndsAt helix.Time `json:"ends_at"`
+	}
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	internalEvent := events.Poll{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		PollID:           event.ID,
+		Title:            event.Title,
+		Choices:          data.NewPollChoicesFromHelix(event.Choices),
+		Status:           event.Status,
+		StartedAt:        event.StartedAt.Time,
+	}
+	if event.ChannelPointsVoting.IsEnabled {
+		internalEvent.ChannelPointsPerVote = event.ChannelPointsVoting.AmountPerVote
+	}
+	if !event.EndsAt.IsZero() {
+		internalEvent.EndsAt = &event.EndsAt.Time
+	}
+	if !event.EndedAt.IsZero() {
+		internalEvent.EndedAt = &event.EndedAt.Time
+	}
+
+	err = notify(ctx, internalEvent)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send poll to core")
+		return err
+	}
+
+	return nil
+}
+
+// channelPrediction handles begin, progress, lock and end, they differ only
+// in timestamps and status.
+func (c *WebhookController) channelPrediction(
+	ctx context.Context,
+	raw json.RawMessage,
+	notify func(context.Context, events.Prediction) error,
+) error {
+	var event struct {
+		helix.EventSubChannelPredictionEndEvent
+		LocksAt  helix.Time `json:"locks_at"`
+		LockedAt helix.Time `json:"locked_at"`
+	}
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	internalEvent := events.Prediction{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		PredictionID:     event.ID,
+		Title:            event.Title,
+		Outcomes:         data.NewPredictionOutcomesFromEventSub(event.Outcomes),
+		WinningOutcomeID: event.WinningOutcomeID,
+		Status:           event.Status,
+		StartedAt:        event.StartedAt.Time,
+	}
+	if !event.LocksAt.IsZero() {
+		internalEvent.LocksAt = &event.LocksAt.Time
+	}
+	if !event.LockedAt.IsZero() {
+		internalEvent.LockedAt = &event.LockedAt.Time
+	}
+	if !event.EndedAt.IsZero() {
+		internalEvent.EndedAt = &event.EndedAt.Time
+	}
+
+	err = notify(ctx, internalEvent)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send prediction to core")
+		return err
+	}
+
+	return nil
+}
+
+// hypeTrain handles begin, progress and end of v2 hype train, end event has
+// ended_at and cooldown_ends_at instead of progress, goal and expires_at.
+func (c *WebhookController) hypeTrain(
+	ctx context.Context,
+	raw json.RawMessage,
+	notify func(context.Context, events.HypeTrain) error,
+) error {
+	// helix has structs only for deprecated v1
+	var event struct {
+		ID                   string                       `json:"id"`
+		BroadcasterUserID    string                       `json:"broadcaster_user_id"`
+		BroadcasterUserLogin string                       `json:"broadcaster_user_login"`
+		BroadcasterUserName  string                       `json:"broadcaster_user_name"`
+		Level                int                          `json:"level"`
+		Total                int                          `json:"total"`
+		Progress             int                          `json:"progress"`
+		Goal                 int                          `json:"goal"`
+		TopContributions     []helix.EventSubContribution `json:"top_contributions"`
+		Type                 string                       `json:"type"`
+		IsSharedTrain        bool                         `json:"is_shared_train"`
+		StartedAt            helix.Time                   `json:"started_at"`
+		ExpiresAt            helix.Time                   `json:"expires_at"`
+		EndedAt              helix.Time                   `json:"ended_at"`
+		CooldownEndsAt       helix.Time                   `json:"cooldown_ends_at"`
+	}
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	contributions := make([]events.HypeTrainContribution, 0, len(event.TopContributions))
+	for _, contribution := range event.TopContributions {
+		contributions = append(contributions, events.HypeTrainContribution{
+			UserID:    contribution.UserID,
+			UserLogin: contribution.UserLogin,
+			UserName:  contribution.UserName,
+			Type:      contribution.Type,
+			Total:     contribution.Total,
+		})
+	}
+
+	internalEvent := events.HypeTrain{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		HypeTrainID:      event.ID,
+		Level:            event.Level,
+		Total:            event.Total,
+		Progress:         event.Progress,
+		Goal:             event.Goal,
+		TopContributions: contributions,
+		Type:             event.Type,
+		IsShared:         event.IsSharedTrain,
+		StartedAt:        event.StartedAt.Time,
+	}
+	if !event.ExpiresAt.IsZero() {
+		internalEvent.ExpiresAt = &event.ExpiresAt.Time
+	}
+	if !event.EndedAt.IsZero() {
+		internalEvent.EndedAt = &event.EndedAt.Time
+	}
+	if !event.CooldownEndsAt.IsZero() {
+		internalEvent.CooldownEndsAt = &event.CooldownEndsAt.Time
+	}
+
+	err = notify(ctx, internalEvent)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send hype train to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) channelAdBreakBegin(ctx context.Context, raw json.RawMessage) error {
+	// helix has no struct for ad break
+	var event struct {
+		BroadcasterUserID    string     `json:"broadcaster_user_id"`
+		BroadcasterUserLogin string     `json:"broadcaster_user_login"`
+		BroadcasterUserName  string     `json:"broadcaster_user_name"`
+		RequesterUserID      string     `json:"requester_user_id"`
+		RequesterUserLogin   string     `json:"requester_user_login"`
+		RequesterUserName    string     `json:"requester_user_name"`
+		DurationSeconds      int        `json:"duration_seconds"`
+		IsAutomatic          bool       `json:"is_automatic"`
+		StartedAt            helix.Time `json:"started_at"`
+	}
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = c.platformModule.AdBreakBeginNotify(ctx, events.AdBreakBegin{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		Duration:         event.DurationSeconds,
+		IsAutomatic:      event.IsAutomatic,
+		RequesterID:      event.RequesterUserID,
+		RequesterLogin:   event.RequesterUserLogin,
+		RequesterName:    event.RequesterUserName,
+		StartedAt:        event.StartedAt.Time,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send ad break begin to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) channelModerate(ctx context.Context, messageID string, raw json.RawMessage) error {
+	var event data.ChannelModerateEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	internalEvent, err := data.NewModerationActionFromEventSub(event, raw)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot parse moderate action payload", "err", err, "action", event.Action)
+		return apperror.New(apperror.CodeInvalidInput, "cannot parse moderate action payload", err)
+	}
+	internalEvent.EventCommon = common
+
+	// audit trail is saved before notifying core, so retry doesn't lose it
+	err = c.moderationService.ActionCreate(ctx, messageID, internalEvent)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot save moderation action")
+		return err
+	}
+
+	err = c.platformModule.ModerationActionNotify(ctx, internalEvent)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send moderation action to core")
+		return err
+	}
+
+	return nil
+}
+
+// automodMessage handles hold and update, update event additionally has
+// moderator and status.
+func (c *WebhookController) automodMessage(
+	ctx context.Context,
+	raw json.RawMessage,
+	notify func(context.Context, events.AutomodMessage) error,
+) error {
+	// helix has no struct for automod message
+	var event struct {
+		BroadcasterUserID    string `json:"broadcaster_user_id"`
+		BroadcasterUserLogin string `json:"broadcaster_user_login"`
+		BroadcasterUserName  string `json:"broadcaster_user_name"`
+		UserID               string `json:"user_id"`
+		UserLogin            string `json:"user_login"`
+		UserName             string `json:"user_name"`
+		ModeratorUserID      string `json:"moderator_user_id"`
+		ModeratorUserLogin   string `json:"moderator_user_login"`
+		ModeratorUserName    string `json:"moderator_user_name"`
+		MessageID            string `json:"message_id"`
+		Message              struct {
+			Text string `json:"text"`
+		} `json:"message"`
+		Category string     `json:"category"`
+		Level    int        `json:"level"`
+		Status   string     `json:"status"`
+		HeldAt   helix.Time `json:"held_at"`
+	}
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	err = notify(ctx, events.AutomodMessage{
+		EventCommon:      common,
+		BroadcasterLogin: event.BroadcasterUserLogin,
+		BroadcasterName:  event.BroadcasterUserName,
+		MessageID:        event.MessageID,
+		Message:          event.Message.Text,
+		ChatterID:        event.UserID,
+		ChatterLogin:     event.UserLogin,
+		ChatterName:      event.UserName,
+		Category:         event.Category,
+		Level:            event.Level,
+		HeldAt:           event.HeldAt.Time,
+		ModeratorID:      event.ModeratorUserID,
+		ModeratorLogin:   event.ModeratorUserLogin,
+		ModeratorName:    event.ModeratorUserName,
+		Status:           event.Status,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send automod message to core")
+		return err
+	}
+
+	return nil
+}
+
+// sharedChat handles begin, update and end of shared chat session, end event
+// has no participants.
+func (c *WebhookController) sharedChat(
+	ctx context.Context,
+	raw json.RawMessage,
+	notify func(context.Context, events.SharedChatSession) error,
+) error {
+	var event data.SharedChatEvent
+	err := decodeEvent(raw, &event)
+	if err != nil {
+		return err
+	}
+
+	common, err := c.eventCommon(ctx, event.BroadcasterUserID)
+	if err != nil {
+		return err
+	}
+
+	participants := make([]events.SharedChatParticipant, 0, len(event.Participants))
+	participantIDs := make([]string, 0, len(event.Participants))
+	for _, participant := range event.Participants {
+		participants = append(participants, events.SharedChatParticipant{
+			BroadcasterID:    participant.BroadcasterUserID,
+			BroadcasterLogin: participant.BroadcasterUserLogin,
+			BroadcasterName:  participant.BroadcasterUserName,
+		})
+		participantIDs = append(participantIDs, participant.BroadcasterUserID)
+	}
+
+	if len(event.Participants) == 0 {
+		err = c.sharedChatService.SessionDelete(ctx, event.BroadcasterUserID)
+	} else {
+		err = c.sharedChatService.SessionSet(ctx, event.BroadcasterUserID, data.SharedChatSession{
+			ID:                event.SessionID,
+			HostBroadcasterID: event.HostBroadcasterUserID,
+			ParticipantIDs:    participantIDs,
+		})
+	}
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot track shared chat session", "sessionID", event.SessionID)
+		return err
+	}
+
+	err = notify(ctx, events.SharedChatSession{
+		EventCommon:          common,
+		BroadcasterLogin:     event.BroadcasterUserLogin,
+		BroadcasterName:      event.BroadcasterUserName,
+		SessionID:            event.SessionID,
+		HostBroadcasterID:    event.HostBroadcasterUserID,
+		HostBroadcasterLogin: event.HostBroadcasterUserLogin,
+		HostBroadcasterName:  event.HostBroadcasterUserName,
+		Participants:         participants,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send shared chat session to core")
+		return err
+	}
+
+	return nil
+}
+
+func (c *WebhookController) revocation(ctx context.Context, sub helix.EventSubSubscription) error {
+	broadcasterID := data.GetConditionBroadcasterID(sub.Condition)
+
+	c.logger.WarnContext(ctx, "subscription revoked",
+		"sub", sub.ID,
+		"subType", sub.Type,
+		"status", sub.Status,
+		"broadcasterID", broadcasterID,
+	)
+
+	common, err := c.eventCommon(ctx, broadcasterID)
+	if err != nil {
+		return err
+	}
+
+	if data.RevocationDisablesBot(sub) {
+		err = c.botService.SelectedBotChangeStatus(ctx, common.UserID, false)
 		if err != nil {
-			c.logger.ErrorContext(ctx.Request().Context(), "cannot send message to core")
-			return nil
+			c.logger.ErrorContext(ctx, "cannot disable selected bot", "userID", common.UserID)
+			return err
 		}
 	}
 
+	err = c.platformModule.SubscriptionRevokedNotify(ctx, events.SubscriptionRevoked{
+		EventCommon:      common,
+		SubscriptionID:   sub.ID,
+		SubscriptionType: sub.Type,
+		Reason:           sub.Status,
+	})
+	if err != nil {
+		c.logger.ErrorContext(ctx, "cannot send revocation to core")
+		return err
+	}
+
 	return nil
 }
@@ -3,62 +3,144 @@ package middleware
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
-	
 	"net/http"
+	"time"
 
 	"github.com/arnokay/arnobot-shared/applog"
 	"github.com/arnokay/arnobot-shared/middlewares"
 	"github.com/arnokay/arnobot-shared/apperror"
+	"github.com/arnokay/arnobot-shared/pkg/assert"
 
 	"github.com/labstack/echo/v4"
+	"github.com/nats-io/nats.go/jetstream"
 	"github.com/nicklaw5/helix/v2"
 
 	"github.com/arnokay/arnobot-twitch/internal/config"
 )
 
+const (
+	headerMessageID        = "Twitch-Eventsub-Message-Id"
+	headerMessageType      = "Twitch-Eventsub-Message-Type"
+	headerMessageTimestamp = "Twitch-Eventsub-Message-Timestamp"
+
+	// maxClockSkew is how far ahead of our clock twitch timestamp can be
+	maxClockSkew = time.Minute
+)
+
 type Middlewares struct {
 	logger applog.Logger
 
+	cache jetstream.KeyValue
+
+	secret        string
+	maxBodySize   int64
+	maxMessageAge time.Duration
+	messageIDTTL  time.Duration
+
 	AuthMiddlewares *middlewares.AuthMiddlewares
 }
 
 func New(
+	cache jetstream.KeyValue,
 	authMiddlewares *middlewares.AuthMiddlewares,
 ) *Middlewares {
 	logger := applog.NewServiceLogger("app-middleware")
 
+	maxMessageAge, err := time.ParseDuration(config.Config.Webhooks.MaxMessageAge)
+	assert.NoError(err, "middleware: cannot parse webhook max message age")
+	messageIDTTL, err := time.ParseDuration(config.Config.Webhooks.MessageIDTTL)
+	assert.NoError(err, "middleware: cannot parse webhook message id ttl")
+
 	return &Middlewares{
 		logger:          logger,
+		cache:           cache,
+		secret:          config.Config.Webhooks.Secret,
+		maxBodySize:     config.Config.Webhooks.MaxBodySize,
+		maxMessageAge:   maxMessageAge,
+		messageIDTTL:    messageIDTTL,
 		AuthMiddlewares: authMiddlewares,
 	}
 }
 
 func (m *Middlewares) VerifyTwitchWebhook(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		// TODO: handle length of request body
-		body, err := io.ReadAll(c.Request().Body)
+		if c.Request().ContentLength > m.maxBodySize {
+			m.logger.ErrorContext(c.Request().Context(), "body is too large", "length", c.Request().ContentLength)
+			return echo.ErrStatusRequestEntityTooLarge
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request().Body, m.maxBodySize+1))
 		if err != nil {
 			m.logger.ErrorContext(c.Request().Context(), "cannot read body", "err", err)
 			return apperror.ErrUnauthorized
 		}
 		c.Request().Body.Close()
+		if int64(len(body)) > m.maxBodySize {
+			m.logger.ErrorContext(c.Request().Context(), "body is too large", "length", len(body))
+			return echo.ErrStatusRequestEntityTooLarge
+		}
 		c.Request().Body = io.NopCloser(bytes.NewReader(body))
 
 		// TODO: maybe move to db per webhook?
-		if !helix.VerifyEventSubNotification(config.Config.Webhooks.Secret, c.Request().Header, string(body)) {
+		if !helix.VerifyEventSubNotification(m.secret, c.Request().Header, string(body)) {
 			m.logger.ErrorContext(c.Request().Context(), "unverified attempt to access webhook")
 			return apperror.ErrUnauthorized
 		}
 
-		msgType := c.Request().Header.Get("Twitch-Eventsub-Message-Type")
+		msgType := c.Request().Header.Get(headerMessageType)
 		if msgType == "" {
 			m.logger.ErrorContext(c.Request().Context(), "message type is empty")
 			return apperror.ErrUnauthorized
 		}
 
 		if msgType != "webhook_callback_verification" {
-			return next(c)
+			timestamp, err := time.Parse(time.RFC3339Nano, c.Request().Header.Get(headerMessageTimestamp))
+			if err != nil {
+				m.logger.ErrorContext(c.Request().Context(), "cannot parse message timestamp", "err", err)
+				return apperror.ErrInvalidInput
+			}
+			if time.Since(timestamp) > m.maxMessageAge {
+				m.logger.ErrorContext(c.Request().Context(), "message is too old", "timestamp", timestamp)
+				return apperror.ErrInvalidInput
+			}
+			if time.Until(timestamp) > maxClockSkew {
+				m.logger.ErrorContext(c.Request().Context(), "message is from the future", "timestamp", timestamp)
+				return apperror.ErrInvalidInput
+			}
+
+			msgID := c.Request().Header.Get(headerMessageID)
+			if msgID == "" {
+				m.logger.ErrorContext(c.Request().Context(), "message id is empty")
+				return apperror.ErrInvalidInput
+			}
+
+			_, err = m.cache.Create(
+				c.Request().Context(),
+				"eventsub.msg."+msgID,
+				[]byte(timestamp.Format(time.RFC3339Nano)),
+				jetstream.KeyTTL(m.messageIDTTL),
+			)
+			if err != nil {
+				if errors.Is(err, jetstream.ErrKeyExists) {
+					m.logger.DebugContext(c.Request().Context(), "duplicate message", "messageID", msgID)
+					return c.NoContent(http.StatusNoContent)
+				}
+				// better to process a message twice than to lose it
+				m.logger.ErrorContext(c.Request().Context(), "cannot remember message id", "err", err, "messageID", msgID)
+			}
+
+			err = next(c)
+			if err != nil {
+				// message is not stored, so twitch redelivery has to be processed
+				delErr := m.cache.Delete(c.Request().Context(), "eventsub.msg."+msgID)
+				if delErr != nil {
+					m.logger.ErrorContext(c.Request().Context(), "cannot forget message id", "err", delErr, "messageID", msgID)
+				}
+			}
+
+			return err
 		}
 
 		var event struct {
@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arnokay/arnobot-shared/apperror"
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/labstack/echo/v4"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const testSecret = "test-webhook-secret"
+
+// fakeKV keeps keys in memory, only what message id dedup needs.
+type fakeKV struct {
+	jetstream.KeyValue
+
+	mu   sync.Mutex
+	keys map[string][]byte
+}
+
+func (kv *fakeKV) Create(ctx context.Context, key string, value []byte, opts ...jetstream.KVCreateOpt) (uint64, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	if _, ok := kv.keys[key]; ok {
+		return 0, jetstream.ErrKeyExists
+	}
+	kv.keys[key] = value
+
+	return 1, nil
+}
+
+func (kv *fakeKV) Delete(ctx context.Context, key string, opts ...jetstream.KVDeleteOpt) error {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+
+	delete(kv.keys, key)
+
+	return nil
+}
+
+func newTestMiddlewares() *Middlewares {
+	return &Middlewares{
+		logger:        applog.NewServiceLogger("app-middleware"),
+		cache:         &fakeKV{keys: make(map[string][]byte)},
+		secret:        testSecret,
+		maxBodySize:   64,
+		maxMessageAge: 10 * time.Minute,
+		messageIDTTL:  15 * time.Minute,
+	}
+}
+
+// newWebhookRequest returns notification signed the way twitch signs it.
+func newWebhookRequest(messageID string, timestamp time.Time, body string) *http.Request {
+	ts := timestamp.Format(time.RFC3339Nano)
+
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(messageID + ts + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(body))
+	req.Header.Set(headerMessageID, messageID)
+	req.Header.Set(headerMessageType, "notification")
+	req.Header.Set(headerMessageTimestamp, ts)
+	req.Header.Set("Twitch-Eventsub-Message-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+
+	return req
+}
+
+// serve runs request through VerifyTwitchWebhook, it returns the response,
+// whether next handler was called and middleware error.
+func serve(m *Middlewares, req *http.Request) (*httptest.ResponseRecorder, bool, error) {
+	called := false
+	handler := m.VerifyTwitchWebhook(func(c echo.Context) error {
+		called = true
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	err := handler(echo.New().NewContext(req, rec))
+
+	return rec, called, err
+}
+
+func TestVerifyTwitchWebhook(t *testing.T) {
+	m := newTestMiddlewares()
+
+	_, called, err := serve(m, newWebhookRequest("msg-1", time.Now(), `{}`))
+	if err != nil {
+		t.Fatalf("valid message error = %v", err)
+	}
+	if !called {
+		t.Fatal("valid message is not handled")
+	}
+
+	req := newWebhookRequest("msg-2", time.Now(), `{}`)
+	req.Header.Set("Twitch-Eventsub-Message-Signature", "sha256=00")
+	_, called, err = serve(m, req)
+	if !errors.Is(err, apperror.ErrUnauthorized) || called {
+		t.Errorf("wrong signature: error = %v, handled = %v, want %v", err, called, apperror.ErrUnauthorized)
+	}
+}
+
+func TestVerifyTwitchWebhookDuplicate(t *testing.T) {
+	m := newTestMiddlewares()
+
+	_, _, err := serve(m, newWebhookRequest("msg-1", time.Now(), `{}`))
+	if err != nil {
+		t.Fatalf("first message error = %v", err)
+	}
+
+	rec, called, err := serve(m, newWebhookRequest("msg-1", time.Now(), `{}`))
+	if err != nil {
+		t.Fatalf("duplicate message error = %v", err)
+	}
+	if called {
+		t.Error("duplicate message is handled again")
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("duplicate message status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+}
+
+func TestVerifyTwitchWebhookRetryAfterError(t *testing.T) {
+	m := newTestMiddlewares()
+
+	handler := m.VerifyTwitchWebhook(func(c echo.Context) error {
+		return apperror.ErrInternal
+	})
+	err := handler(echo.New().NewContext(newWebhookRequest("msg-1", time.Now(), `{}`), httptest.NewRecorder()))
+	if !errors.Is(err, apperror.ErrInternal) {
+		t.Fatalf("failed message error = %v, want %v", err, apperror.ErrInternal)
+	}
+
+	// twitch redelivers message that was not stored
+	_, called, err := serve(m, newWebhookRequest("msg-1", time.Now(), `{}`))
+	if err != nil || !called {
+		t.Errorf("redelivered message: error = %v, handled = %v, want handled", err, called)
+	}
+}
+
+func TestVerifyTwitchWebhookBodySize(t *testing.T) {
+	m := newTestMiddlewares()
+	body := `{"event":"` + strings.Repeat("a", 64) + `"}`
+
+	tests := []struct {
+		name          string
+		contentLength int64
+	}{
+		{"content length", int64(len(body))},
+		// chunked request has no content length, body is still limited
+		{"no content length", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newWebhookRequest("msg-1", time.Now(), body)
+			req.ContentLength = tt.contentLength
+
+			_, called, err := serve(m, req)
+
+			var httpErr *echo.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("error = %v, want status %d", err, http.StatusRequestEntityTooLarge)
+			}
+			if called {
+				t.Error("too large message is handled")
+			}
+		})
+	}
+}
+
+func TestVerifyTwitchWebhookTimestamp(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp time.Time
+		wantErr   bool
+	}{
+		{"now", time.Now(), false},
+		{"within max age", time.Now().Add(-9 * time.Minute), false},
+		{"stale", time.Now().Add(-11 * time.Minute), true},
+		{"within clock skew", time.Now().Add(maxClockSkew / 2), false},
+		{"future", time.Now().Add(2 * maxClockSkew), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMiddlewares()
+
+			_, called, err := serve(m, newWebhookRequest("msg-1", tt.timestamp, `{}`))
+			if tt.wantErr {
+				if !errors.Is(err, apperror.ErrInvalidInput) || called {
+					t.Errorf("error = %v, handled = %v, want %v", err, called, apperror.ErrInvalidInput)
+				}
+				return
+			}
+			if err != nil || !called {
+				t.Errorf("error = %v, handled = %v, want handled", err, called)
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/arnokay/arnobot-shared/pkg/assert"
 )
@@ -26,11 +28,17 @@ type config struct {
 	MB       MBConfig
 	DB       DBConfig
 	Webhooks Webhooks
+	Ingest   IngestConfig
+	ChatSend ChatSendConfig
+	EventSub EventSubConfig
 }
 
 type TwitchConfig struct {
 	ClientID     string
 	ClientSecret string
+	// SuppressSharedChat drops messages that came from other channels of
+	// shared chat
+	SuppressSharedChat bool
 }
 
 type DBConfig struct {
@@ -51,8 +59,53 @@ type MBConfig struct {
 }
 
 type Webhooks struct {
-	Secret   string
-	Callback string
+	Secret        string
+	Callback      string
+	MaxBodySize   int64
+	MaxMessageAge string
+	MessageIDTTL  string
+}
+
+type IngestConfig struct {
+	Workers    int
+	MaxDeliver int
+	// Backoff is comma separated list of retry delays, last one is used for
+	// the rest of retries
+	Backoff   string
+	DLQMaxAge string
+}
+
+type ChatSendConfig struct {
+	MaxAttempts int
+	// Backoff is comma separated list of retry delays, last one is used for
+	// the rest of retries
+	Backoff string
+}
+
+// ParseBackoff parses comma separated list of retry delays.
+func ParseBackoff(backoff string) ([]time.Duration, error) {
+	var delays []time.Duration
+	for _, s := range strings.Split(backoff, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		delays = append(delays, d)
+	}
+
+	return delays, nil
+}
+
+const (
+	EventSubTransportWebhook   = "webhook"
+	EventSubTransportWebsocket = "websocket"
+)
+
+type EventSubConfig struct {
+	// Transport is webhook or websocket, websocket does not need public
+	// callback url
+	Transport    string
+	WebsocketURL string
 }
 
 var Config *config
@@ -74,10 +127,22 @@ func Load() *config {
 	flag.IntVar(&Config.Global.LogLevel, "log-level", Config.Global.LogLevel, "Minimal Log Level (default: -4)")
 	flag.StringVar(&Config.Webhooks.Secret, "wh-secret", os.Getenv(ENV_TWITCH_WH_SECRET), "secret for subscribing to webhooks")
   flag.StringVar(&Config.Webhooks.Callback, "wh-callback", os.Getenv(ENV_TWITCH_WH_CALLBACK), "twitch secret")
+	flag.Int64Var(&Config.Webhooks.MaxBodySize, "wh-max-body-size", 1<<20, "max webhook request body size in bytes")
+	flag.StringVar(&Config.Webhooks.MaxMessageAge, "wh-max-message-age", "10m", "max age of webhook notification before it is rejected")
+	flag.StringVar(&Config.Webhooks.MessageIDTTL, "wh-message-id-ttl", "15m", "how long seen webhook message ids are remembered")
+	flag.IntVar(&Config.Ingest.Workers, "ingest-workers", 8, "number of workers handling webhook notifications")
+	flag.IntVar(&Config.Ingest.MaxDeliver, "ingest-max-deliver", 5, "how many times notification is handled before it goes to dead letter queue")
+	flag.StringVar(&Config.Ingest.Backoff, "ingest-backoff", "1s,5s,30s,2m", "comma separated delays between notification retries")
+	flag.StringVar(&Config.Ingest.DLQMaxAge, "ingest-dlq-max-age", "168h", "how long dead lettered notifications are kept")
+	flag.IntVar(&Config.ChatSend.MaxAttempts, "chat-send-max-attempts", 3, "how many times chat message is sent before it goes to dead letter queue")
+	flag.StringVar(&Config.ChatSend.Backoff, "chat-send-backoff", "1s,3s", "comma separated delays between chat message retries")
+	flag.StringVar(&Config.EventSub.Transport, "eventsub-transport", EventSubTransportWebhook, "eventsub transport: webhook or websocket")
+	flag.StringVar(&Config.EventSub.WebsocketURL, "eventsub-ws-url", "wss://eventsub.wss.twitch.tv/ws", "eventsub websocket url, can point to a mock server")
 	flag.StringVar(&Config.Global.BaseURL, "base-url", os.Getenv(ENV_BASE_URL), "public url")
 	flag.IntVar(&Config.Global.Port, "port", Config.Global.Port, "http port")
 	flag.StringVar(&Config.Twitch.ClientID, "client-id", os.Getenv(ENV_TWITCH_CLIENT_ID), "twitch client id")
 	flag.StringVar(&Config.Twitch.ClientSecret, "client-secret", os.Getenv(ENV_TWITCH_CLIENT_SECRET), "twitch client id")
+	flag.BoolVar(&Config.Twitch.SuppressSharedChat, "shared-chat-suppress", false, "do not forward messages and notices from other channels of shared chat")
 	flag.StringVar(&Config.DB.DSN, "db-dsn", os.Getenv(ENV_DB_DSN), "DB DSN")
 	flag.IntVar(&Config.DB.MaxIdleConns, "db-max-idle-conns", 25, "PostgreSQL max idle connections")
 	flag.IntVar(&Config.DB.MaxOpenConns, "db-max-open-conns", 25, "PostgreSQL max open connections")
@@ -85,5 +150,10 @@ func Load() *config {
 
 	flag.Parse()
 
+	assert.Assert(
+		Config.EventSub.Transport == EventSubTransportWebhook || Config.EventSub.Transport == EventSubTransportWebsocket,
+		fmt.Sprintf("eventsub-transport: unknown transport %q", Config.EventSub.Transport),
+	)
+
 	return Config
 }
@@ -0,0 +1,95 @@
+package data
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+)
+
+type AdStart struct {
+	BroadcasterID string `json:"broadcasterId"`
+	// Length in seconds, twitch rounds it down to 30, 60, 90, 120, 150 or 180
+	Length int `json:"length"`
+}
+
+type AdStartResult struct {
+	Length  int    `json:"length"`
+	Message string `json:"message"`
+	// RetryAfter is number of seconds until next commercial can be started
+	RetryAfter int `json:"retryAfter"`
+}
+
+type AdScheduleGet struct {
+	BroadcasterID string `json:"broadcasterId"`
+}
+
+type AdSnooze struct {
+	BroadcasterID string `json:"broadcasterId"`
+}
+
+type AdSchedule struct {
+	SnoozeCount     int        `json:"snoozeCount"`
+	SnoozeRefreshAt *time.Time `json:"snoozeRefreshAt,omitempty"`
+	NextAdAt        *time.Time `json:"nextAdAt,omitempty"`
+	// Duration of the next ad in seconds
+	Duration int        `json:"duration"`
+	LastAdAt *time.Time `json:"lastAdAt,omitempty"`
+	// PrerollFreeTime in seconds
+	PrerollFreeTime int `json:"prerollFreeTime"`
+}
+
+// HelixAdSchedule is response of get ad schedule and snooze next ad
+// endpoints, helix client has no support for them.
+type HelixAdSchedule struct {
+	SnoozeCount     int    `json:"snooze_count"`
+	SnoozeRefreshAt AdTime `json:"snooze_refresh_at"`
+	NextAdAt        AdTime `json:"next_ad_at"`
+	Duration        int    `json:"duration"`
+	LastAdAt        AdTime `json:"last_ad_at"`
+	PrerollFreeTime int    `json:"preroll_free_time"`
+}
+
+// AdTime is a timestamp of ads endpoints, twitch sends it either as RFC3339
+// string or as unix seconds (number or string), empty means not set.
+type AdTime struct {
+	time.Time
+}
+
+func (t *AdTime) UnmarshalJSON(b []byte) error {
+	b = bytes.Trim(b, `"`)
+	if len(b) == 0 || string(b) == "null" || string(b) == "0" {
+		return nil
+	}
+
+	if unix, err := strconv.ParseInt(string(b), 10, 64); err == nil {
+		t.Time = time.Unix(unix, 0).UTC()
+		return nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, string(b))
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+
+	return nil
+}
+
+func (t AdTime) Ptr() *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t.Time
+}
+
+func NewAdScheduleFromHelix(schedule HelixAdSchedule) AdSchedule {
+	return AdSchedule{
+		SnoozeCount:     schedule.SnoozeCount,
+		SnoozeRefreshAt: schedule.SnoozeRefreshAt.Ptr(),
+		NextAdAt:        schedule.NextAdAt.Ptr(),
+		Duration:        schedule.Duration,
+		LastAdAt:        schedule.LastAdAt.Ptr(),
+		PrerollFreeTime: schedule.PrerollFreeTime,
+	}
+}
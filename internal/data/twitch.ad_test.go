@@ -0,0 +1,42 @@
+package data
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestAdTimeUnmarshalJSON(t *testing.T) {
+	at := time.Date(2025, 7, 8, 20, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		json    string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "rfc3339", json: `"2025-07-08T20:30:00Z"`, want: at},
+		{name: "unix number", json: `1752006600`, want: at},
+		{name: "unix string", json: `"1752006600"`, want: at},
+		{name: "empty string", json: `""`},
+		{name: "null", json: `null`},
+		{name: "zero", json: `0`},
+		{name: "invalid", json: `"tomorrow"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got AdTime
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) error = %v, wantErr %v", tt.json, err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.json, got.Time, tt.want)
+			}
+			if tt.want.IsZero() && got.Ptr() != nil {
+				t.Errorf("Ptr() = %v, want nil", got.Ptr())
+			}
+		})
+	}
+}
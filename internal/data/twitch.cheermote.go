@@ -0,0 +1,105 @@
+package data
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+)
+
+// cheermote is a prefix that ends with a letter followed by the bits amount,
+// e.g. Cheer100, 4Head50
+var cheermoteRegexp = regexp.MustCompile(`^([A-Za-z0-9]*[A-Za-z])(\d+)$`)
+
+var cheermoteTiers = []int{100000, 10000, 5000, 1000, 100, 1}
+
+// ParseCheerFragments splits channel.cheer message into text and cheermote
+// fragments, channel.cheer doesn't send fragments like channel.chat.message
+// does.
+// Only words with one of prefixes (lowercase) are cheermotes, without
+// prefixes any word that looks like a cheermote is. Whole message is a single
+// text fragment when parsed cheermotes don't add up to bits, e.g. "gg100"
+// is not a cheermote.
+func ParseCheerFragments(text string, prefixes []string, bits int) []events.MessageFragment {
+	var known map[string]bool
+	if len(prefixes) > 0 {
+		known = make(map[string]bool, len(prefixes))
+		for _, prefix := range prefixes {
+			known[prefix] = true
+		}
+	}
+
+	var fragments []events.MessageFragment
+	var plain strings.Builder
+	total := 0
+
+	flush := func() {
+		if plain.Len() == 0 {
+			return
+		}
+		fragments = append(fragments, events.MessageFragment{
+			Type: events.FragmentTypeText,
+			Text: plain.String(),
+		})
+		plain.Reset()
+	}
+
+	for i, word := range strings.Split(text, " ") {
+		if i != 0 {
+			plain.WriteString(" ")
+		}
+
+		cheermote := parseCheermote(word, known)
+		if cheermote == nil {
+			plain.WriteString(word)
+			continue
+		}
+
+		flush()
+		fragments = append(fragments, events.MessageFragment{
+			Type:      events.FragmentTypeCheermote,
+			Text:      word,
+			Cheermote: cheermote,
+		})
+		total += cheermote.Bits
+	}
+	flush()
+
+	if total != bits {
+		return []events.MessageFragment{{
+			Type: events.FragmentTypeText,
+			Text: text,
+		}}
+	}
+
+	return fragments
+}
+
+func parseCheermote(word string, known map[string]bool) *events.FragmentCheermote {
+	match := cheermoteRegexp.FindStringSubmatch(word)
+	if match == nil {
+		return nil
+	}
+	if known != nil && !known[strings.ToLower(match[1])] {
+		return nil
+	}
+
+	bits, err := strconv.Atoi(match[2])
+	if err != nil || bits <= 0 {
+		return nil
+	}
+
+	cheermote := &events.FragmentCheermote{
+		Prefix: match[1],
+		Bits:   bits,
+	}
+	for _, tier := range cheermoteTiers {
+		if bits >= tier {
+			cheermote.Tier = tier
+			break
+		}
+	}
+
+	return cheermote
+}
@@ -0,0 +1,94 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+)
+
+func text(s string) events.MessageFragment {
+	return events.MessageFragment{Type: events.FragmentTypeText, Text: s}
+}
+
+func cheer(word, prefix string, bits, tier int) events.MessageFragment {
+	return events.MessageFragment{
+		Type: events.FragmentTypeCheermote,
+		Text: word,
+		Cheermote: &events.FragmentCheermote{
+			Prefix: prefix,
+			Bits:   bits,
+			Tier:   tier,
+		},
+	}
+}
+
+func TestParseCheerFragments(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		prefixes []string
+		bits     int
+		want     []events.MessageFragment
+	}{
+		{
+			name: "cheermotes between text",
+			text: "hello Cheer100 and 4Head5000 bye",
+			bits: 5100,
+			want: []events.MessageFragment{
+				text("hello "),
+				cheer("Cheer100", "Cheer", 100, 100),
+				text(" and "),
+				cheer("4Head5000", "4Head", 5000, 5000),
+				text(" bye"),
+			},
+		},
+		{
+			name: "tier is the highest one reached",
+			text: "Cheer99 Cheer10001",
+			bits: 10100,
+			want: []events.MessageFragment{
+				cheer("Cheer99", "Cheer", 99, 1),
+				text(" "),
+				cheer("Cheer10001", "Cheer", 10001, 10000),
+			},
+		},
+		{
+			name:     "only known prefixes",
+			text:     "gg100 Cheer100",
+			prefixes: []string{"cheer"},
+			bits:     100,
+			want: []events.MessageFragment{
+				text("gg100 "),
+				cheer("Cheer100", "Cheer", 100, 100),
+			},
+		},
+		{
+			name: "total is less than bits",
+			text: "Cheer100 gg",
+			bits: 200,
+			want: []events.MessageFragment{text("Cheer100 gg")},
+		},
+		{
+			name: "total is more than bits",
+			text: "Cheer100 gg100",
+			bits: 100,
+			want: []events.MessageFragment{text("Cheer100 gg100")},
+		},
+		{
+			name: "no cheermotes",
+			text: "just text",
+			bits: 100,
+			want: []events.MessageFragment{text("just text")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseCheerFragments(tt.text, tt.prefixes, tt.bits)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCheerFragments(%q, %v, %d) =\n%+v\nwant\n%+v", tt.text, tt.prefixes, tt.bits, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package data
+
+import (
+	"github.com/nicklaw5/helix/v2"
+)
+
+// GetConditionBroadcasterID returns the broadcaster the subscription belongs
+// to. Raid subscriptions don't have broadcaster_user_id set, only the
+// direction of the raid.
+func GetConditionBroadcasterID(condition helix.EventSubCondition) string {
+	if condition.BroadcasterUserID != "" {
+		return condition.BroadcasterUserID
+	}
+	if condition.ToBroadcasterUserID != "" {
+		return condition.ToBroadcasterUserID
+	}
+
+	return condition.FromBroadcasterUserID
+}
+
+// RevocationDisablesBot reports whether the bot cannot work after the
+// subscription is revoked. Only subscriptions the bot had from the start are
+// required, the rest are best-effort, and losing the authorization takes
+// every subscription down.
+func RevocationDisablesBot(sub helix.EventSubSubscription) bool {
+	switch sub.Status {
+	case helix.EventSubStatusAuthorizationRevoked, helix.EventSubStatusUserRemoved:
+		return true
+	}
+
+	switch sub.Type {
+	case helix.EventSubTypeChannelChatMessage, helix.EventSubTypeStreamOnline, helix.EventSubTypeStreamOffline:
+		return true
+	}
+
+	return false
+}
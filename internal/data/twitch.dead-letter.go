@@ -0,0 +1,63 @@
+package data
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type DeadLetterKind string
+
+const (
+	DeadLetterKindEventSub DeadLetterKind = "eventsub"
+	DeadLetterKindChatSend DeadLetterKind = "chat-send"
+)
+
+// DeadLetter is a webhook notification or outbound request that failed all
+// attempts, Seq is its sequence in the dead letter stream.
+type DeadLetter struct {
+	Seq  uint64         `json:"seq"`
+	Kind DeadLetterKind `json:"kind"`
+	// Subject is where message was originally published, replay publishes it
+	// there again
+	Subject          string          `json:"subject"`
+	MessageID        string          `json:"messageID,omitempty"`
+	SubscriptionType string          `json:"subscriptionType,omitempty"`
+	Error            string          `json:"error"`
+	Attempts         int             `json:"attempts"`
+	FailedAt         time.Time       `json:"failedAt"`
+	Body             json.RawMessage `json:"body,omitempty"`
+}
+
+type DeadLetterCreate struct {
+	Kind     DeadLetterKind
+	Subject  string
+	Header   map[string][]string
+	Body     []byte
+	Err      error
+	Attempts int
+}
+
+type DeadLetterList struct {
+	// Kind is optional, all dead letters are listed without it
+	Kind  DeadLetterKind `json:"kind,omitempty"`
+	Limit int            `json:"limit,omitempty"`
+	// After is sequence after which dead letters are listed
+	After uint64 `json:"after,omitempty"`
+}
+
+type DeadLetterGet struct {
+	Seq uint64 `json:"seq"`
+}
+
+type DeadLetterReplay struct {
+	Seq uint64 `json:"seq"`
+}
+
+type DeadLetterPurge struct {
+	// Seq purges single dead letter, otherwise every dead letter of Kind is
+	// purged. All has to be set to purge every dead letter, so empty request
+	// doesn't wipe the queue
+	Seq  uint64         `json:"seq,omitempty"`
+	Kind DeadLetterKind `json:"kind,omitempty"`
+	All  bool           `json:"all,omitempty"`
+}
@@ -0,0 +1,38 @@
+package data
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventSubNotification is a verified eventsub message as it was received from
+// twitch, it is stored in the ingest stream and handled by workers.
+type EventSubNotification struct {
+	MessageID        string
+	MessageType      string
+	SubscriptionType string
+	Body             []byte
+}
+
+// EventSubWebsocketMessage is a message of eventsub websocket, payload of
+// notification and revocation has the same shape as webhook body.
+type EventSubWebsocketMessage struct {
+	Metadata EventSubWebsocketMetadata `json:"metadata"`
+	Payload  json.RawMessage           `json:"payload"`
+}
+
+type EventSubWebsocketMetadata struct {
+	MessageID           string    `json:"message_id"`
+	MessageType         string    `json:"message_type"`
+	MessageTimestamp    time.Time `json:"message_timestamp"`
+	SubscriptionType    string    `json:"subscription_type"`
+	SubscriptionVersion string    `json:"subscription_version"`
+}
+
+type EventSubWebsocketSession struct {
+	ID                      string    `json:"id"`
+	Status                  string    `json:"status"`
+	KeepaliveTimeoutSeconds int       `json:"keepalive_timeout_seconds"`
+	ReconnectURL            string    `json:"reconnect_url"`
+	ConnectedAt             time.Time `json:"connected_at"`
+}
@@ -0,0 +1,56 @@
+package data
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nicklaw5/helix/v2"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+)
+
+// NewMessageFragmentsFromEventSub converts chat message fragments, emote
+// positions are rune indexes in the message text.
+func NewMessageFragmentsFromEventSub(fragments []helix.EventSubChatMessageFragment) []events.MessageFragment {
+	result := make([]events.MessageFragment, 0, len(fragments))
+
+	position := 0
+	for _, fragment := range fragments {
+		// same weird \U000e0000 as in the message text
+		text := strings.Replace(fragment.Text, "\U000e0000", "", 1)
+		length := utf8.RuneCountInString(text)
+
+		internal := events.MessageFragment{
+			Type: events.FragmentType(fragment.Type),
+			Text: text,
+		}
+
+		switch fragment.Type {
+		case helix.EventSubChatMessageFragmentTypeCheermote:
+			internal.Cheermote = &events.FragmentCheermote{
+				Prefix: fragment.Cheermote.Prefix,
+				Bits:   int(fragment.Cheermote.Bits),
+				Tier:   fragment.Cheermote.Tier,
+			}
+		case helix.EventSubChatMessageFragmentTypeEmote:
+			internal.Emote = &events.FragmentEmote{
+				ID:      fragment.Emote.ID,
+				SetID:   fragment.Emote.EmoteSetID,
+				OwnerID: fragment.Emote.OwnerID,
+				Begin:   position,
+				End:     position + length - 1,
+			}
+		case helix.EventSubChatMessageFragmentTypeMention:
+			internal.Mention = &events.FragmentMention{
+				UserID:    fragment.Mention.UserID,
+				UserLogin: fragment.Mention.UserLogin,
+				UserName:  fragment.Mention.UserName,
+			}
+		}
+
+		result = append(result, internal)
+		position += length
+	}
+
+	return result
+}
@@ -0,0 +1,85 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nicklaw5/helix/v2"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+)
+
+func emote(word, id string, begin, end int) events.MessageFragment {
+	return events.MessageFragment{
+		Type: events.FragmentTypeEmote,
+		Text: word,
+		Emote: &events.FragmentEmote{
+			ID:    id,
+			Begin: begin,
+			End:   end,
+		},
+	}
+}
+
+func TestNewMessageFragmentsFromEventSub(t *testing.T) {
+	kappa := helix.EventSubChatMessageFragment{
+		Type:  helix.EventSubChatMessageFragmentTypeEmote,
+		Text:  "Kappa",
+		Emote: helix.EventSubChatMessageEmote{ID: "25"},
+	}
+
+	tests := []struct {
+		name      string
+		fragments []helix.EventSubChatMessageFragment
+		want      []events.MessageFragment
+	}{
+		{
+			name: "ascii",
+			fragments: []helix.EventSubChatMessageFragment{
+				{Type: helix.EventSubChatMessageFragmentTypeText, Text: "hi "},
+				kappa,
+			},
+			want: []events.MessageFragment{
+				text("hi "),
+				emote("Kappa", "25", 3, 7),
+			},
+		},
+		{
+			name: "offsets are runes, not bytes",
+			fragments: []helix.EventSubChatMessageFragment{
+				{Type: helix.EventSubChatMessageFragmentTypeText, Text: "привет 😀 "},
+				kappa,
+				{Type: helix.EventSubChatMessageFragmentTypeText, Text: " ё "},
+				kappa,
+			},
+			want: []events.MessageFragment{
+				text("привет 😀 "),
+				emote("Kappa", "25", 9, 13),
+				text(" ё "),
+				emote("Kappa", "25", 17, 21),
+			},
+		},
+		{
+			name: "tag character is not counted",
+			fragments: []helix.EventSubChatMessageFragment{
+				kappa,
+				{Type: helix.EventSubChatMessageFragmentTypeText, Text: " \U000e0000"},
+				kappa,
+			},
+			want: []events.MessageFragment{
+				emote("Kappa", "25", 0, 4),
+				text(" "),
+				emote("Kappa", "25", 6, 10),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMessageFragmentsFromEventSub(tt.fragments)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewMessageFragmentsFromEventSub() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
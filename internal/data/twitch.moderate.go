@@ -0,0 +1,98 @@
+package data
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/nicklaw5/helix/v2"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+)
+
+// ChannelModerateEvent is a common part of channel.moderate v2 event, helix
+// has no struct for it. Action specific payload is stored under the key
+// returned by ModerateActionPayloadKey.
+type ChannelModerateEvent struct {
+	BroadcasterUserID          string `json:"broadcaster_user_id"`
+	BroadcasterUserLogin       string `json:"broadcaster_user_login"`
+	BroadcasterUserName        string `json:"broadcaster_user_name"`
+	SourceBroadcasterUserID    string `json:"source_broadcaster_user_id"`
+	SourceBroadcasterUserLogin string `json:"source_broadcaster_user_login"`
+	SourceBroadcasterUserName  string `json:"source_broadcaster_user_name"`
+	ModeratorUserID            string `json:"moderator_user_id"`
+	ModeratorUserLogin         string `json:"moderator_user_login"`
+	ModeratorUserName          string `json:"moderator_user_name"`
+	Action                     string `json:"action"`
+}
+
+// moderateTarget has every target related field of action payloads, each
+// action uses only part of them.
+type moderateTarget struct {
+	UserID           string     `json:"user_id"`
+	UserLogin        string     `json:"user_login"`
+	UserName         string     `json:"user_name"`
+	Reason           string     `json:"reason"`
+	ExpiresAt        helix.Time `json:"expires_at"`
+	MessageID        string     `json:"message_id"`
+	MessageBody      string     `json:"message_body"`
+	ModeratorMessage string     `json:"moderator_message"`
+}
+
+func ModerateActionPayloadKey(action string) string {
+	switch {
+	case strings.HasSuffix(action, "_blocked_term"), strings.HasSuffix(action, "_permitted_term"):
+		return "automod_terms"
+	case strings.HasSuffix(action, "_unban_request"):
+		return "unban_request"
+	}
+
+	return action
+}
+
+// NewModerationActionFromEventSub converts channel.moderate event without
+// EventCommon. Payload of the action is kept in Details as is.
+func NewModerationActionFromEventSub(event ChannelModerateEvent, raw json.RawMessage) (events.ModerationAction, error) {
+	result := events.ModerationAction{
+		BroadcasterLogin:      event.BroadcasterUserLogin,
+		BroadcasterName:       event.BroadcasterUserName,
+		SourceBroadcasterID:   event.SourceBroadcasterUserID,
+		SourceBroadcasterName: event.SourceBroadcasterUserName,
+		ModeratorID:           event.ModeratorUserID,
+		ModeratorLogin:        event.ModeratorUserLogin,
+		ModeratorName:         event.ModeratorUserName,
+		Action:                event.Action,
+	}
+
+	var payloads map[string]json.RawMessage
+	err := json.Unmarshal(raw, &payloads)
+	if err != nil {
+		return result, err
+	}
+
+	payload := payloads[ModerateActionPayloadKey(event.Action)]
+	if len(payload) == 0 || string(payload) == "null" {
+		return result, nil
+	}
+	result.Details = payload
+
+	var target moderateTarget
+	err = json.Unmarshal(payload, &target)
+	if err != nil {
+		return result, err
+	}
+
+	result.TargetID = target.UserID
+	result.TargetLogin = target.UserLogin
+	result.TargetName = target.UserName
+	result.Reason = target.Reason
+	if target.ModeratorMessage != "" {
+		result.Reason = target.ModeratorMessage
+	}
+	result.MessageID = target.MessageID
+	result.MessageBody = target.MessageBody
+	if !target.ExpiresAt.IsZero() {
+		result.ExpiresAt = &target.ExpiresAt.Time
+	}
+
+	return result, nil
+}
@@ -0,0 +1,164 @@
+package data
+
+import (
+	"strings"
+
+	"github.com/nicklaw5/helix/v2"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+)
+
+// NewChatNotificationFromEventSub converts chat notification without
+// EventCommon, payload is set only for the notice type of the event. Shared
+// chat notices get the same type and payload as local ones, they can be told
+// apart by SourceBroadcasterID.
+func NewChatNotificationFromEventSub(sharedEvent ChatNotificationEvent) events.ChatNotification {
+	event := normalizeSharedChatNotice(sharedEvent)
+
+	result := events.ChatNotification{
+		BroadcasterLogin:   event.BroadcasterUserLogin,
+		BroadcasterName:    event.BroadcasterUserName,
+		MessageID:          event.MessageID,
+		ChatterID:          event.ChatterUserID,
+		ChatterLogin:       event.ChatterUserLogin,
+		ChatterName:        event.ChatterUserName,
+		ChatterIsAnonymous: event.ChatterIsAnonymous,
+		SystemMessage:      event.SystemMessage,
+		Message:            strings.Replace(event.Message.Text, "\U000e0000", "", 1),
+		NoticeType:         events.NoticeType(event.NoticeType),
+		Fragments:          NewMessageFragmentsFromEventSub(event.Message.Fragments),
+
+		SourceBroadcasterID:    sharedEvent.SourceBroadcasterUserID,
+		SourceBroadcasterLogin: sharedEvent.SourceBroadcasterUserLogin,
+		SourceBroadcasterName:  sharedEvent.SourceBroadcasterUserName,
+	}
+
+	switch event.NoticeType {
+	case helix.EventSubChannelNotificationSub:
+		result.Sub = &events.NoticeSubscription{
+			Tier:           event.Sub.SubTier,
+			IsPrime:        event.Sub.IsPrime,
+			DurationMonths: event.Sub.DurationMonths,
+		}
+	case helix.EventSubChannelNotificationResub:
+		result.Resub = &events.NoticeResubscription{
+			Tier:             event.Resub.SubTier,
+			IsPrime:          event.Resub.IsPrime,
+			IsGift:           event.Resub.IsGift,
+			CumulativeMonths: event.Resub.CumulativeMonths,
+			DurationMonths:   event.Resub.DurationMonths,
+			StreakMonths:     event.Resub.StreakMonths,
+		}
+		if event.Resub.IsGift {
+			result.Resub.Gifter = newNoticeGifter(
+				event.Resub.GifterIsAnonymous,
+				event.Resub.GifterUserID,
+				event.Resub.GifterUserLogin,
+				event.Resub.GifterUserName,
+			)
+		}
+	case helix.EventSubChannelNotificationSubGift:
+		result.SubGift = &events.NoticeSubGift{
+			Tier:            event.SubGift.SubTier,
+			DurationMonths:  event.SubGift.DurationMonths,
+			CumulativeTotal: event.SubGift.CumulativeTotal,
+			RecipientID:     event.SubGift.RecipientUserID,
+			RecipientLogin:  event.SubGift.RecipientUserLogin,
+			RecipientName:   event.SubGift.RecipientUserName,
+			CommunityGiftID: event.SubGift.CommunityGiftID,
+		}
+	case helix.EventSubChannelNotificationCommunitySubGift:
+		result.CommunitySubGift = &events.NoticeCommunitySubGift{
+			ID:              event.CommunitySubGift.ID,
+			Tier:            event.CommunitySubGift.SubTier,
+			Total:           event.CommunitySubGift.Total,
+			CumulativeTotal: event.CommunitySubGift.CumulativeTotal,
+		}
+	case helix.EventSubChannelNotificationGiftPaidUpgrade:
+		result.GiftPaidUpgrade = newNoticeGifter(
+			event.GiftPaidUpgrade.GifterIsAnonymous,
+			event.GiftPaidUpgrade.GifterUserID,
+			event.GiftPaidUpgrade.GifterUserLogin,
+			event.GiftPaidUpgrade.GifterUserName,
+		)
+	case helix.EventSubChannelNotificationPrimePaidUpgrade:
+		result.PrimePaidUpgrade = &events.NoticePrimePaidUpgrade{
+			Tier: event.PrimePaidUpgrade.SubTier,
+		}
+	case helix.EventSubChannelNotificationRaid:
+		result.Raid = &events.NoticeRaid{
+			UserID:          event.Raid.UserID,
+			UserLogin:       event.Raid.UserLogin,
+			UserName:        event.Raid.UserName,
+			ViewerCount:     event.Raid.ViewerCount,
+			ProfileImageURL: event.Raid.ProfileImageURL,
+		}
+	case helix.EventSubChannelNotificationPayItForward:
+		result.PayItForward = newNoticeGifter(
+			event.PayItForward.GifterIsAnonymous,
+			event.PayItForward.GifterUserID,
+			event.PayItForward.GifterUserLogin,
+			event.PayItForward.GifterUserName,
+		)
+	case helix.EventSubChannelNotificationAnnouncement:
+		result.Announcement = &events.NoticeAnnouncement{
+			Color: event.Announcement.Color,
+		}
+	case helix.EventSubChannelNotificationBitsBadgeTier:
+		result.BitsBadgeTier = &events.NoticeBitsBadgeTier{
+			Tier: event.BitsBadgeTier.Tier,
+		}
+	case helix.EventSubChannelNotificationCharityDonation:
+		result.CharityDonation = &events.NoticeCharityDonation{
+			CharityName:   event.CharityDonation.CharityName,
+			Amount:        event.CharityDonation.Amount.Value,
+			DecimalPlaces: event.CharityDonation.Amount.DecimalPlace,
+			Currency:      event.CharityDonation.Amount.Currency,
+		}
+	}
+
+	return result
+}
+
+func normalizeSharedChatNotice(event ChatNotificationEvent) helix.EventSubChannelChatNotificationEvent {
+	notice := event.EventSubChannelChatNotificationEvent
+	if !strings.HasPrefix(string(notice.NoticeType), "shared_chat_") {
+		return notice
+	}
+
+	notice.NoticeType = helix.EventSubChannelChatNotificationType(strings.TrimPrefix(string(notice.NoticeType), "shared_chat_"))
+	switch notice.NoticeType {
+	case helix.EventSubChannelNotificationSub:
+		notice.Sub = event.SharedChatSub
+	case helix.EventSubChannelNotificationResub:
+		notice.Resub = event.SharedChatResub
+	case helix.EventSubChannelNotificationSubGift:
+		notice.SubGift = event.SharedChatSubGift
+	case helix.EventSubChannelNotificationCommunitySubGift:
+		notice.CommunitySubGift = event.SharedChatCommunitySubGift
+	case helix.EventSubChannelNotificationGiftPaidUpgrade:
+		notice.GiftPaidUpgrade = event.SharedChatGiftPaidUpgrade
+	case helix.EventSubChannelNotificationPrimePaidUpgrade:
+		notice.PrimePaidUpgrade = event.SharedChatPrimePaidUpgrade
+	case helix.EventSubChannelNotificationRaid:
+		notice.Raid = event.SharedChatRaid
+	case helix.EventSubChannelNotificationPayItForward:
+		notice.PayItForward = event.SharedChatPayItForward
+	case helix.EventSubChannelNotificationAnnouncement:
+		notice.Announcement = event.SharedChatAnnouncement
+	}
+
+	return notice
+}
+
+func newNoticeGifter(isAnonymous bool, id, login, name string) *events.NoticeGifter {
+	if isAnonymous {
+		return &events.NoticeGifter{IsAnonymous: true}
+	}
+
+	return &events.NoticeGifter{
+		ID:    id,
+		Login: login,
+		Name:  name,
+	}
+}
@@ -0,0 +1,89 @@
+package data
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+)
+
+func TestNewChatNotificationFromEventSubSharedChat(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+		want  events.ChatNotification
+	}{
+		{
+			name: "local sub",
+			event: `{
+				"notice_type": "sub",
+				"sub": {"sub_tier": "1000", "is_prime": true, "duration_months": 1}
+			}`,
+			want: events.ChatNotification{
+				NoticeType: "sub",
+				Sub:        &events.NoticeSubscription{Tier: "1000", IsPrime: true, DurationMonths: 1},
+			},
+		},
+		{
+			name: "shared chat sub",
+			event: `{
+				"source_broadcaster_user_id": "2",
+				"source_broadcaster_user_login": "other",
+				"source_broadcaster_user_name": "Other",
+				"notice_type": "shared_chat_sub",
+				"shared_chat_sub": {"sub_tier": "2000", "is_prime": false, "duration_months": 3}
+			}`,
+			want: events.ChatNotification{
+				NoticeType:             "sub",
+				Sub:                    &events.NoticeSubscription{Tier: "2000", DurationMonths: 3},
+				SourceBroadcasterID:    "2",
+				SourceBroadcasterLogin: "other",
+				SourceBroadcasterName:  "Other",
+			},
+		},
+		{
+			name: "shared chat raid",
+			event: `{
+				"source_broadcaster_user_id": "2",
+				"notice_type": "shared_chat_raid",
+				"shared_chat_raid": {"user_id": "3", "user_login": "raider", "user_name": "Raider", "viewer_count": 42}
+			}`,
+			want: events.ChatNotification{
+				NoticeType:          "raid",
+				Raid:                &events.NoticeRaid{UserID: "3", UserLogin: "raider", UserName: "Raider", ViewerCount: 42},
+				SourceBroadcasterID: "2",
+			},
+		},
+		{
+			name: "shared chat announcement",
+			event: `{
+				"source_broadcaster_user_id": "2",
+				"notice_type": "shared_chat_announcement",
+				"shared_chat_announcement": {"color": "PURPLE"}
+			}`,
+			want: events.ChatNotification{
+				NoticeType:          "announcement",
+				Announcement:        &events.NoticeAnnouncement{Color: "PURPLE"},
+				SourceBroadcasterID: "2",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event ChatNotificationEvent
+			err := json.Unmarshal([]byte(tt.event), &event)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := NewChatNotificationFromEventSub(event)
+			// fragments are covered by their own test
+			got.Fragments = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewChatNotificationFromEventSub() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
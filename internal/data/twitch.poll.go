@@ -0,0 +1,54 @@
+package data
+
+import (
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+)
+
+type PollCreate struct {
+	BroadcasterID string   `json:"broadcasterId"`
+	Title         string   `json:"title"`
+	Choices       []string `json:"choices"`
+	// Duration in seconds, from 15 to 1800
+	Duration int `json:"duration"`
+	// ChannelPointsPerVote enables voting with channel points when > 0
+	ChannelPointsPerVote int `json:"channelPointsPerVote"`
+}
+
+type PollEnd struct {
+	BroadcasterID string `json:"broadcasterId"`
+	ID            string `json:"id"`
+	// Archive ends the poll and hides it from the channel, otherwise results
+	// are still shown to viewers
+	Archive bool `json:"archive"`
+}
+
+type Poll struct {
+	ID                   string              `json:"id"`
+	BroadcasterID        string              `json:"broadcasterId"`
+	Title                string              `json:"title"`
+	Choices              []events.PollChoice `json:"choices"`
+	ChannelPointsPerVote int                 `json:"channelPointsPerVote"`
+	Status               string              `json:"status"`
+	Duration             int                 `json:"duration"`
+	StartedAt            time.Time           `json:"startedAt"`
+	EndedAt              *time.Time          `json:"endedAt,omitempty"`
+}
+
+func NewPollChoicesFromHelix(choices []helix.PollChoice) []events.PollChoice {
+	result := make([]events.PollChoice, 0, len(choices))
+	for _, choice := range choices {
+		result = append(result, events.PollChoice{
+			ID:                 choice.ID,
+			Title:              choice.Title,
+			Votes:              choice.Votes,
+			ChannelPointsVotes: choice.ChannelPointsVotes,
+			BitsVotes:          choice.BitsVotes,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,98 @@
+package data
+
+import (
+	"time"
+
+	"github.com/nicklaw5/helix/v2"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+)
+
+type PredictionCreate struct {
+	BroadcasterID string   `json:"broadcasterId"`
+	Title         string   `json:"title"`
+	Outcomes      []string `json:"outcomes"`
+	// PredictionWindow in seconds, from 30 to 1800
+	PredictionWindow int `json:"predictionWindow"`
+}
+
+type PredictionLock struct {
+	BroadcasterID string `json:"broadcasterId"`
+	ID            string `json:"id"`
+}
+
+type PredictionResolve struct {
+	BroadcasterID    string `json:"broadcasterId"`
+	ID               string `json:"id"`
+	WinningOutcomeID string `json:"winningOutcomeId"`
+}
+
+type PredictionCancel struct {
+	BroadcasterID string `json:"broadcasterId"`
+	ID            string `json:"id"`
+}
+
+type Prediction struct {
+	ID               string                     `json:"id"`
+	BroadcasterID    string                     `json:"broadcasterId"`
+	Title            string                     `json:"title"`
+	WinningOutcomeID string                     `json:"winningOutcomeId,omitempty"`
+	Outcomes         []events.PredictionOutcome `json:"outcomes"`
+	PredictionWindow int                        `json:"predictionWindow"`
+	Status           string                     `json:"status"`
+	CreatedAt        time.Time                  `json:"createdAt"`
+	LockedAt         *time.Time                 `json:"lockedAt,omitempty"`
+	EndedAt          *time.Time                 `json:"endedAt,omitempty"`
+}
+
+func NewPredictionOutcomesFromHelix(outcomes []helix.Outcomes) []events.PredictionOutcome {
+	result := make([]events.PredictionOutcome, 0, len(outcomes))
+	for _, outcome := range outcomes {
+		topPredictors := make([]events.TopPredictor, 0, len(outcome.TopPredictors))
+		for _, predictor := range outcome.TopPredictors {
+			topPredictors = append(topPredictors, events.TopPredictor{
+				UserID:            predictor.UserID,
+				UserLogin:         predictor.UserLogin,
+				UserName:          predictor.UserName,
+				ChannelPointsUsed: predictor.ChannelPointsUsed,
+				ChannelPointsWon:  predictor.ChannelPointsWon,
+			})
+		}
+		result = append(result, events.PredictionOutcome{
+			ID:            outcome.ID,
+			Title:         outcome.Title,
+			Color:         outcome.Color,
+			Users:         outcome.Users,
+			ChannelPoints: outcome.ChannelPoints,
+			TopPredictors: topPredictors,
+		})
+	}
+
+	return result
+}
+
+func NewPredictionOutcomesFromEventSub(outcomes []helix.EventSubOutcome) []events.PredictionOutcome {
+	result := make([]events.PredictionOutcome, 0, len(outcomes))
+	for _, outcome := range outcomes {
+		topPredictors := make([]events.TopPredictor, 0, len(outcome.TopPredictors))
+		for _, predictor := range outcome.TopPredictors {
+			topPredictors = append(topPredictors, events.TopPredictor{
+				UserID:            predictor.UserID,
+				UserLogin:         predictor.UserLogin,
+				UserName:          predictor.UserName,
+				ChannelPointsUsed: predictor.ChannelPointsUsed,
+				ChannelPointsWon:  predictor.ChannelPointWon,
+			})
+		}
+		result = append(result, events.PredictionOutcome{
+			ID:            outcome.ID,
+			Title:         outcome.Title,
+			Color:         outcome.Color,
+			Users:         outcome.Users,
+			ChannelPoints: outcome.ChannelPoints,
+			TopPredictors: topPredictors,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,77 @@
+package data
+
+type RedemptionStatus string
+
+const (
+	RedemptionFulfilled RedemptionStatus = "FULFILLED"
+	RedemptionCanceled  RedemptionStatus = "CANCELED"
+)
+
+type RewardRedemptionStatusUpdate struct {
+	BroadcasterID string           `json:"broadcasterId"`
+	RewardID      string           `json:"rewardId"`
+	RedemptionID  string           `json:"redemptionId"`
+	Status        RedemptionStatus `json:"status"`
+}
+
+type Reward struct {
+	ID                    string `json:"id"`
+	BroadcasterID         string `json:"broadcasterId"`
+	Title                 string `json:"title"`
+	Prompt                string `json:"prompt"`
+	Cost                  int    `json:"cost"`
+	BackgroundColor       string `json:"backgroundColor"`
+	IsEnabled             bool   `json:"isEnabled"`
+	IsPaused              bool   `json:"isPaused"`
+	IsInStock             bool   `json:"isInStock"`
+	IsUserInputRequired   bool   `json:"isUserInputRequired"`
+	MaxPerStream          int    `json:"maxPerStream"`
+	MaxPerUserPerStream   int    `json:"maxPerUserPerStream"`
+	GlobalCooldownSeconds int    `json:"globalCooldownSeconds"`
+	SkipRequestQueue      bool   `json:"skipRequestQueue"`
+}
+
+// RewardCreate creates reward owned by our client id, limits and cooldown
+// are disabled when 0.
+type RewardCreate struct {
+	BroadcasterID         string `json:"broadcasterId"`
+	Title                 string `json:"title"`
+	Prompt                string `json:"prompt"`
+	Cost                  int    `json:"cost"`
+	BackgroundColor       string `json:"backgroundColor"`
+	IsEnabled             bool   `json:"isEnabled"`
+	IsUserInputRequired   bool   `json:"isUserInputRequired"`
+	MaxPerStream          int    `json:"maxPerStream"`
+	MaxPerUserPerStream   int    `json:"maxPerUserPerStream"`
+	GlobalCooldownSeconds int    `json:"globalCooldownSeconds"`
+	SkipRequestQueue      bool   `json:"skipRequestQueue"`
+}
+
+// RewardUpdate updates only set fields, limits and cooldown are disabled
+// when set to 0.
+type RewardUpdate struct {
+	BroadcasterID         string  `json:"broadcasterId"`
+	ID                    string  `json:"id"`
+	Title                 *string `json:"title"`
+	Prompt                *string `json:"prompt"`
+	Cost                  *int    `json:"cost"`
+	BackgroundColor       *string `json:"backgroundColor"`
+	IsEnabled             *bool   `json:"isEnabled"`
+	IsPaused              *bool   `json:"isPaused"`
+	IsUserInputRequired   *bool   `json:"isUserInputRequired"`
+	MaxPerStream          *int    `json:"maxPerStream"`
+	MaxPerUserPerStream   *int    `json:"maxPerUserPerStream"`
+	GlobalCooldownSeconds *int    `json:"globalCooldownSeconds"`
+	SkipRequestQueue      *bool   `json:"skipRequestQueue"`
+}
+
+type RewardDelete struct {
+	BroadcasterID string `json:"broadcasterId"`
+	ID            string `json:"id"`
+}
+
+type RewardsGet struct {
+	BroadcasterID string `json:"broadcasterId"`
+	// OnlyManageable returns only rewards created by our client id
+	OnlyManageable bool `json:"onlyManageable"`
+}
@@ -3,6 +3,8 @@ package data
 import (
 	"github.com/arnokay/arnobot-shared/data"
 	"github.com/nicklaw5/helix/v2"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
 )
 
 func GetChatterRole(badges []helix.EventSubChatBadge) data.ChatterRole {
@@ -14,13 +16,28 @@ func GetChatterRole(badges []helix.EventSubChatBadge) data.ChatterRole {
 		if badge.SetID == "vip" && role < data.ChatterVIP {
 			role = data.ChatterVIP
 		}
-		if badge.SetID == "moderator" && role < data.ChatterModerator {
+		if (badge.SetID == "moderator" || badge.SetID == "lead_moderator") && role < data.ChatterModerator {
 			role = data.ChatterModerator
 		}
-		if badge.SetID == "broadcaster" && role < data.ChatterModerator {
+		if badge.SetID == "broadcaster" && role < data.ChatterBroadcaster {
 			role = data.ChatterBroadcaster
 		}
 	}
 
 	return role
 }
+
+// NewBadgesFromEventSub keeps every badge, info has e.g. number of subscribed
+// months for subscriber and founder badges.
+func NewBadgesFromEventSub(badges []helix.EventSubChatBadge) []events.Badge {
+	result := make([]events.Badge, 0, len(badges))
+	for _, badge := range badges {
+		result = append(result, events.Badge{
+			SetID: badge.SetID,
+			ID:    badge.ID,
+			Info:  badge.Info,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,66 @@
+package data
+
+import (
+	"slices"
+
+	"github.com/nicklaw5/helix/v2"
+)
+
+// SharedChatEvent is channel.shared_chat.begin/update/end event, helix has no
+// struct for it. End event has no participants.
+type SharedChatEvent struct {
+	SessionID                string                  `json:"session_id"`
+	BroadcasterUserID        string                  `json:"broadcaster_user_id"`
+	BroadcasterUserLogin     string                  `json:"broadcaster_user_login"`
+	BroadcasterUserName      string                  `json:"broadcaster_user_name"`
+	HostBroadcasterUserID    string                  `json:"host_broadcaster_user_id"`
+	HostBroadcasterUserLogin string                  `json:"host_broadcaster_user_login"`
+	HostBroadcasterUserName  string                  `json:"host_broadcaster_user_name"`
+	Participants             []SharedChatParticipant `json:"participants"`
+}
+
+type SharedChatParticipant struct {
+	BroadcasterUserID    string `json:"broadcaster_user_id"`
+	BroadcasterUserLogin string `json:"broadcaster_user_login"`
+	BroadcasterUserName  string `json:"broadcaster_user_name"`
+}
+
+// SharedChatSession is stored per broadcaster while the broadcaster is in
+// shared chat.
+type SharedChatSession struct {
+	ID                string   `json:"id"`
+	HostBroadcasterID string   `json:"hostBroadcasterId"`
+	ParticipantIDs    []string `json:"participantIds"`
+}
+
+func (s SharedChatSession) HasParticipant(broadcasterID string) bool {
+	return slices.Contains(s.ParticipantIDs, broadcasterID)
+}
+
+// ChatNotificationEvent is channel.chat.notification with shared chat fields
+// helix doesn't have. Notices from other channels of shared chat come with
+// shared_chat_ prefixed type and payload.
+type ChatNotificationEvent struct {
+	helix.EventSubChannelChatNotificationEvent
+
+	SourceBroadcasterUserID    string `json:"source_broadcaster_user_id"`
+	SourceBroadcasterUserLogin string `json:"source_broadcaster_user_login"`
+	SourceBroadcasterUserName  string `json:"source_broadcaster_user_name"`
+	SourceMessageID            string `json:"source_message_id"`
+
+	SharedChatSub              helix.EventSubChannelChatNotificationSub              `json:"shared_chat_sub"`
+	SharedChatResub            helix.EventSubChannelChatNotificationResub            `json:"shared_chat_resub"`
+	SharedChatSubGift          helix.EventSubChannelChatNotificationSubGift          `json:"shared_chat_sub_gift"`
+	SharedChatCommunitySubGift helix.EventSubChannelChatNotificationCommunitySubGift `json:"shared_chat_community_sub_gift"`
+	SharedChatGiftPaidUpgrade  helix.EventSubChannelChatNotificationGiftPaidUpgrade  `json:"shared_chat_gift_paid_upgrade"`
+	SharedChatPrimePaidUpgrade helix.EventSubChannelChatNotificationPrimePaidUpgrade `json:"shared_chat_prime_paid_upgrade"`
+	SharedChatRaid             helix.EventSubChannelChatNotificationRaid             `json:"shared_chat_raid"`
+	SharedChatPayItForward     helix.EventSubChannelChatNotificationPayItForward     `json:"shared_chat_pay_it_forward"`
+	SharedChatAnnouncement     helix.EventSubChannelChatNotificationAnnouncement     `json:"shared_chat_announcement"`
+}
+
+// IsFromOtherChannel reports whether the event originated in another channel
+// of shared chat.
+func IsFromOtherChannel(broadcasterID, sourceBroadcasterID string) bool {
+	return sourceBroadcasterID != "" && sourceBroadcasterID != broadcasterID
+}
@@ -3,6 +3,9 @@ package dbtransform
 import (
 	"github.com/arnokay/arnobot-shared/data"
 	"github.com/arnokay/arnobot-shared/db"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+	"github.com/arnokay/arnobot-twitch/internal/twitchdb"
 )
 
 func NewPlatformDefaultBotFromDB(fromDB db.TwitchDefaultBot) data.PlatformDefaultBot {
@@ -21,6 +24,16 @@ func NewPlatformSelectedBotFromDB(fromDB db.TwitchSelectedBot) data.PlatformSele
 	}
 }
 
+func NewPlatformSelectedBotFromTwitchDB(fromDB twitchdb.TwitchSelectedBot) data.PlatformSelectedBot {
+	return data.PlatformSelectedBot{
+		UserID:        fromDB.UserID,
+		BotID:         fromDB.BotID,
+		BroadcasterID: fromDB.BroadcasterID,
+		Enabled:       fromDB.Enabled,
+		UpdatedAt:     fromDB.UpdatedAt,
+	}
+}
+
 func NewPlatformBotFromDB(fromDB db.TwitchBot) data.PlatformBot {
 	return data.PlatformBot{
 		UserID:        fromDB.UserID,
@@ -44,3 +57,35 @@ func NewPlatformBotsGetToDB(d data.PlatformBotsGet) db.TwitchBotsGetParams {
 		BroadcasterID: d.BroadcasterID,
 	}
 }
+
+func NewModerationActionCreateToDB(eventSubMessageID string, d events.ModerationAction) twitchdb.TwitchModerationActionCreateParams {
+	var details []byte
+	if len(d.Details) > 0 {
+		details = d.Details
+	}
+
+	return twitchdb.TwitchModerationActionCreateParams{
+		EventsubMessageID:   eventSubMessageID,
+		BroadcasterID:       d.BroadcasterID,
+		SourceBroadcasterID: nullString(d.SourceBroadcasterID),
+		ModeratorID:         d.ModeratorID,
+		ModeratorLogin:      d.ModeratorLogin,
+		Action:              d.Action,
+		TargetID:            nullString(d.TargetID),
+		TargetLogin:         nullString(d.TargetLogin),
+		Reason:              nullString(d.Reason),
+		ExpiresAt:           d.ExpiresAt,
+		MessageID:           nullString(d.MessageID),
+		MessageBody:         nullString(d.MessageBody),
+		Details:             details,
+	}
+}
+
+// nullString stores empty string as NULL.
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
@@ -0,0 +1,593 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	sharedEvents "github.com/arnokay/arnobot-shared/events"
+)
+
+// Message extends the shared chat message, it is published to the same topic
+// so consumers that don't know about fragments still can read it.
+type Message struct {
+	sharedEvents.Message
+
+	Fragments   []MessageFragment `json:"fragments,omitempty"`
+	Badges      []Badge           `json:"badges"`
+	Color       string            `json:"color,omitempty"`
+	MessageType MessageType       `json:"messageType"`
+	// RewardID is set when message was sent with channel points reward
+	RewardID string `json:"rewardId,omitempty"`
+	// Bits is set when message has cheer
+	Bits int `json:"bits,omitempty"`
+	// Source fields are set when message was sent in shared chat, source
+	// broadcaster is the channel where message was actually sent
+	SourceBroadcasterID    string `json:"sourceBroadcasterId,omitempty"`
+	SourceBroadcasterLogin string `json:"sourceBroadcasterLogin,omitempty"`
+	SourceBroadcasterName  string `json:"sourceBroadcasterName,omitempty"`
+	SourceMessageID        string `json:"sourceMessageId,omitempty"`
+	SharedChatSessionID    string `json:"sharedChatSessionId,omitempty"`
+	// Reply is set when message is a reply, parent id is also in ReplyTo
+	Reply *MessageReply `json:"reply,omitempty"`
+}
+
+// MessageReply has the message that was replied to and the first message of
+// the thread, they are the same for the first reply in the thread.
+type MessageReply struct {
+	ParentMessageID   string `json:"parentMessageId"`
+	ParentMessageBody string `json:"parentMessageBody"`
+	ParentUserID      string `json:"parentUserId"`
+	ParentUserLogin   string `json:"parentUserLogin"`
+	ParentUserName    string `json:"parentUserName"`
+	ThreadMessageID   string `json:"threadMessageId"`
+	ThreadUserID      string `json:"threadUserId"`
+	ThreadUserLogin   string `json:"threadUserLogin"`
+	ThreadUserName    string `json:"threadUserName"`
+}
+
+type MessageType string
+
+const (
+	MessageTypeText                     MessageType = "text"
+	MessageTypeChannelPointsHighlighted MessageType = "channel_points_highlighted"
+	MessageTypeChannelPointsSubOnly     MessageType = "channel_points_sub_only"
+	MessageTypeUserIntro                MessageType = "user_intro"
+	MessageTypePowerUpsMessageEffect    MessageType = "power_ups_message_effect"
+	MessageTypePowerUpsGigantifiedEmote MessageType = "power_ups_gigantified_emote"
+)
+
+// Badge is a chat badge, e.g. subscriber, founder, lead_moderator, artist-badge
+// or staff.
+type Badge struct {
+	SetID string `json:"setId"`
+	ID    string `json:"id"`
+	Info  string `json:"info,omitempty"`
+}
+
+type SubscriptionRevoked struct {
+	sharedEvents.EventCommon
+
+	SubscriptionID   string `json:"subscriptionId"`
+	SubscriptionType string `json:"subscriptionType"`
+	Reason           string `json:"reason"`
+}
+
+type StreamOnline struct {
+	sharedEvents.EventCommon
+
+	StreamID         string    `json:"streamId"`
+	BroadcasterLogin string    `json:"broadcasterLogin"`
+	BroadcasterName  string    `json:"broadcasterName"`
+	Type             string    `json:"type"`
+	StartedAt        time.Time `json:"startedAt"`
+}
+
+type StreamOffline struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string `json:"broadcasterLogin"`
+	BroadcasterName  string `json:"broadcasterName"`
+}
+
+type Follow struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string    `json:"broadcasterLogin"`
+	BroadcasterName  string    `json:"broadcasterName"`
+	FollowerID       string    `json:"followerId"`
+	FollowerLogin    string    `json:"followerLogin"`
+	FollowerName     string    `json:"followerName"`
+	FollowedAt       time.Time `json:"followedAt"`
+}
+
+type Subscribe struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string `json:"broadcasterLogin"`
+	BroadcasterName  string `json:"broadcasterName"`
+	SubscriberID     string `json:"subscriberId"`
+	SubscriberLogin  string `json:"subscriberLogin"`
+	SubscriberName   string `json:"subscriberName"`
+	Tier             string `json:"tier"`
+	IsGift           bool   `json:"isGift"`
+}
+
+type SubscriptionEnd = Subscribe
+
+type SubscriptionMessage struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string  `json:"broadcasterLogin"`
+	BroadcasterName  string  `json:"broadcasterName"`
+	SubscriberID     string  `json:"subscriberId"`
+	SubscriberLogin  string  `json:"subscriberLogin"`
+	SubscriberName   string  `json:"subscriberName"`
+	Tier             string  `json:"tier"`
+	Message          string  `json:"message"`
+	Emotes           []Emote `json:"emotes,omitempty"`
+	CumulativeMonths int     `json:"cumulativeMonths"`
+	// StreakMonths is 0 if subscriber chose not to share the streak
+	StreakMonths   int `json:"streakMonths"`
+	DurationMonths int `json:"durationMonths"`
+}
+
+type SubscriptionGift struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string `json:"broadcasterLogin"`
+	BroadcasterName  string `json:"broadcasterName"`
+	// Gifter is empty if gift is anonymous
+	GifterID        string `json:"gifterId,omitempty"`
+	GifterLogin     string `json:"gifterLogin,omitempty"`
+	GifterName      string `json:"gifterName,omitempty"`
+	Tier            string `json:"tier"`
+	Total           int    `json:"total"`
+	CumulativeTotal int    `json:"cumulativeTotal,omitempty"`
+	IsAnonymous     bool   `json:"isAnonymous"`
+}
+
+type Emote struct {
+	ID    string `json:"id"`
+	Begin int    `json:"begin"`
+	End   int    `json:"end"`
+}
+
+type Cheer struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string `json:"broadcasterLogin"`
+	BroadcasterName  string `json:"broadcasterName"`
+	// Cheerer is empty if cheer is anonymous
+	CheererID    string            `json:"cheererId,omitempty"`
+	CheererLogin string            `json:"cheererLogin,omitempty"`
+	CheererName  string            `json:"cheererName,omitempty"`
+	IsAnonymous  bool              `json:"isAnonymous"`
+	Bits         int               `json:"bits"`
+	Message      string            `json:"message"`
+	Fragments    []MessageFragment `json:"fragments,omitempty"`
+}
+
+type FragmentType string
+
+const (
+	FragmentTypeText      FragmentType = "text"
+	FragmentTypeCheermote FragmentType = "cheermote"
+	FragmentTypeEmote     FragmentType = "emote"
+	FragmentTypeMention   FragmentType = "mention"
+)
+
+type MessageFragment struct {
+	Type      FragmentType       `json:"type"`
+	Text      string             `json:"text"`
+	Cheermote *FragmentCheermote `json:"cheermote,omitempty"`
+	Emote     *FragmentEmote     `json:"emote,omitempty"`
+	Mention   *FragmentMention   `json:"mention,omitempty"`
+}
+
+type FragmentCheermote struct {
+	Prefix string `json:"prefix"`
+	Bits   int    `json:"bits"`
+	Tier   int    `json:"tier"`
+}
+
+type FragmentEmote struct {
+	ID      string `json:"id"`
+	SetID   string `json:"setId"`
+	OwnerID string `json:"ownerId"`
+	// Begin and End are inclusive rune indexes in the message
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+type FragmentMention struct {
+	UserID    string `json:"userId"`
+	UserLogin string `json:"userLogin"`
+	UserName  string `json:"userName"`
+}
+
+type RaidDirection string
+
+const (
+	RaidIncoming RaidDirection = "incoming"
+	RaidOutgoing RaidDirection = "outgoing"
+)
+
+type Raid struct {
+	sharedEvents.EventCommon
+
+	Direction            RaidDirection `json:"direction"`
+	FromBroadcasterID    string        `json:"fromBroadcasterId"`
+	FromBroadcasterLogin string        `json:"fromBroadcasterLogin"`
+	FromBroadcasterName  string        `json:"fromBroadcasterName"`
+	ToBroadcasterID      string        `json:"toBroadcasterId"`
+	ToBroadcasterLogin   string        `json:"toBroadcasterLogin"`
+	ToBroadcasterName    string        `json:"toBroadcasterName"`
+	Viewers              int           `json:"viewers"`
+}
+
+type ShoutoutSend struct {
+	sharedEvents.EventCommon
+
+	ToBroadcasterID string `json:"toBroadcasterId"`
+}
+
+type RewardRedemption struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string    `json:"broadcasterLogin"`
+	BroadcasterName  string    `json:"broadcasterName"`
+	RedemptionID     string    `json:"redemptionId"`
+	RedeemerID       string    `json:"redeemerId"`
+	RedeemerLogin    string    `json:"redeemerLogin"`
+	RedeemerName     string    `json:"redeemerName"`
+	UserInput        string    `json:"userInput,omitempty"`
+	Status           string    `json:"status"`
+	Reward           Reward    `json:"reward"`
+	RedeemedAt       time.Time `json:"redeemedAt"`
+}
+
+type Reward struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Cost   int    `json:"cost"`
+	Prompt string `json:"prompt,omitempty"`
+}
+
+type Poll struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin     string       `json:"broadcasterLogin"`
+	BroadcasterName      string       `json:"broadcasterName"`
+	PollID               string       `json:"pollId"`
+	Title                string       `json:"title"`
+	Choices              []PollChoice `json:"choices"`
+	ChannelPointsPerVote int          `json:"channelPointsPerVote"`
+	// Status is set only when poll has ended
+	Status    string     `json:"status,omitempty"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndsAt    *time.Time `json:"endsAt,omitempty"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+}
+
+type PollChoice struct {
+	ID                 string `json:"id"`
+	Title              string `json:"title"`
+	Votes              int    `json:"votes"`
+	ChannelPointsVotes int    `json:"channelPointsVotes"`
+	BitsVotes          int    `json:"bitsVotes"`
+}
+
+type Prediction struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string              `json:"broadcasterLogin"`
+	BroadcasterName  string              `json:"broadcasterName"`
+	PredictionID     string              `json:"predictionId"`
+	Title            string              `json:"title"`
+	Outcomes         []PredictionOutcome `json:"outcomes"`
+	WinningOutcomeID string              `json:"winningOutcomeId,omitempty"`
+	// Status is set only when prediction is locked or has ended
+	Status    string     `json:"status,omitempty"`
+	StartedAt time.Time  `json:"startedAt"`
+	LocksAt   *time.Time `json:"locksAt,omitempty"`
+	LockedAt  *time.Time `json:"lockedAt,omitempty"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+}
+
+type PredictionOutcome struct {
+	ID            string         `json:"id"`
+	Title         string         `json:"title"`
+	Color         string         `json:"color"`
+	Users         int            `json:"users"`
+	ChannelPoints int            `json:"channelPoints"`
+	TopPredictors []TopPredictor `json:"topPredictors,omitempty"`
+}
+
+type TopPredictor struct {
+	UserID            string `json:"userId"`
+	UserLogin         string `json:"userLogin"`
+	UserName          string `json:"userName"`
+	ChannelPointsUsed int    `json:"channelPointsUsed"`
+	ChannelPointsWon  int    `json:"channelPointsWon"`
+}
+
+type HypeTrain struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string                  `json:"broadcasterLogin"`
+	BroadcasterName  string                  `json:"broadcasterName"`
+	HypeTrainID      string                  `json:"hypeTrainId"`
+	Level            int                     `json:"level"`
+	Total            int                     `json:"total"`
+	Progress         int                     `json:"progress"`
+	Goal             int                     `json:"goal"`
+	TopContributions []HypeTrainContribution `json:"topContributions"`
+	// Type is one of regular, treasure or golden_kappa
+	Type           string     `json:"type"`
+	IsShared       bool       `json:"isShared"`
+	StartedAt      time.Time  `json:"startedAt"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+	EndedAt        *time.Time `json:"endedAt,omitempty"`
+	CooldownEndsAt *time.Time `json:"cooldownEndsAt,omitempty"`
+}
+
+type HypeTrainContribution struct {
+	UserID    string `json:"userId"`
+	UserLogin string `json:"userLogin"`
+	UserName  string `json:"userName"`
+	// Type is one of bits, subscription or other
+	Type  string `json:"type"`
+	Total int64  `json:"total"`
+}
+
+type NoticeType string
+
+const (
+	NoticeTypeSub              NoticeType = "sub"
+	NoticeTypeResub            NoticeType = "resub"
+	NoticeTypeSubGift          NoticeType = "sub_gift"
+	NoticeTypeCommunitySubGift NoticeType = "community_sub_gift"
+	NoticeTypeGiftPaidUpgrade  NoticeType = "gift_paid_upgrade"
+	NoticeTypePrimePaidUpgrade NoticeType = "prime_paid_upgrade"
+	NoticeTypeRaid             NoticeType = "raid"
+	NoticeTypeUnraid           NoticeType = "unraid"
+	NoticeTypePayItForward     NoticeType = "pay_it_forward"
+	NoticeTypeAnnouncement     NoticeType = "announcement"
+	NoticeTypeBitsBadgeTier    NoticeType = "bits_badge_tier"
+	NoticeTypeCharityDonation  NoticeType = "charity_donation"
+)
+
+// ChatNotification is a system message visible in chat, only the field
+// matching NoticeType is set.
+type ChatNotification struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin   string     `json:"broadcasterLogin"`
+	BroadcasterName    string     `json:"broadcasterName"`
+	MessageID          string     `json:"messageId"`
+	ChatterID          string     `json:"chatterId"`
+	ChatterLogin       string     `json:"chatterLogin"`
+	ChatterName        string     `json:"chatterName"`
+	ChatterIsAnonymous bool       `json:"chatterIsAnonymous"`
+	SystemMessage      string     `json:"systemMessage"`
+	Message            string     `json:"message,omitempty"`
+	NoticeType         NoticeType `json:"noticeType"`
+	// SourceBroadcaster is set when notice came from shared chat
+	SourceBroadcasterID    string `json:"sourceBroadcasterId,omitempty"`
+	SourceBroadcasterLogin string `json:"sourceBroadcasterLogin,omitempty"`
+	SourceBroadcasterName  string `json:"sourceBroadcasterName,omitempty"`
+
+	Fragments []MessageFragment `json:"fragments,omitempty"`
+
+	Sub              *NoticeSubscription     `json:"sub,omitempty"`
+	Resub            *NoticeResubscription   `json:"resub,omitempty"`
+	SubGift          *NoticeSubGift          `json:"subGift,omitempty"`
+	CommunitySubGift *NoticeCommunitySubGift `json:"communitySubGift,omitempty"`
+	GiftPaidUpgrade  *NoticeGifter           `json:"giftPaidUpgrade,omitempty"`
+	PrimePaidUpgrade *NoticePrimePaidUpgrade `json:"primePaidUpgrade,omitempty"`
+	Raid             *NoticeRaid             `json:"raid,omitempty"`
+	PayItForward     *NoticeGifter           `json:"payItForward,omitempty"`
+	Announcement     *NoticeAnnouncement     `json:"announcement,omitempty"`
+	BitsBadgeTier    *NoticeBitsBadgeTier    `json:"bitsBadgeTier,omitempty"`
+	CharityDonation  *NoticeCharityDonation  `json:"charityDonation,omitempty"`
+}
+
+type NoticeSubscription struct {
+	Tier           string `json:"tier"`
+	IsPrime        bool   `json:"isPrime"`
+	DurationMonths int    `json:"durationMonths"`
+}
+
+type NoticeResubscription struct {
+	Tier             string `json:"tier"`
+	IsPrime          bool   `json:"isPrime"`
+	IsGift           bool   `json:"isGift"`
+	CumulativeMonths int    `json:"cumulativeMonths"`
+	DurationMonths   int    `json:"durationMonths"`
+	StreakMonths     int    `json:"streakMonths"`
+	// Gifter is set only when IsGift is true
+	Gifter *NoticeGifter `json:"gifter,omitempty"`
+}
+
+type NoticeSubGift struct {
+	Tier            string `json:"tier"`
+	DurationMonths  int    `json:"durationMonths"`
+	CumulativeTotal int    `json:"cumulativeTotal"`
+	RecipientID     string `json:"recipientId"`
+	RecipientLogin  string `json:"recipientLogin"`
+	RecipientName   string `json:"recipientName"`
+	// CommunityGiftID is set when the gift is a part of community sub gift
+	CommunityGiftID string `json:"communityGiftId,omitempty"`
+}
+
+type NoticeCommunitySubGift struct {
+	ID              string `json:"id"`
+	Tier            string `json:"tier"`
+	Total           int    `json:"total"`
+	CumulativeTotal int    `json:"cumulativeTotal"`
+}
+
+type NoticeGifter struct {
+	IsAnonymous bool   `json:"isAnonymous"`
+	ID          string `json:"id,omitempty"`
+	Login       string `json:"login,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+type NoticePrimePaidUpgrade struct {
+	Tier string `json:"tier"`
+}
+
+type NoticeRaid struct {
+	UserID          string `json:"userId"`
+	UserLogin       string `json:"userLogin"`
+	UserName        string `json:"userName"`
+	ViewerCount     int64  `json:"viewerCount"`
+	ProfileImageURL string `json:"profileImageUrl"`
+}
+
+type NoticeAnnouncement struct {
+	Color string `json:"color"`
+}
+
+type NoticeBitsBadgeTier struct {
+	Tier int64 `json:"tier"`
+}
+
+type NoticeCharityDonation struct {
+	CharityName string `json:"charityName"`
+	// Amount in the smallest currency unit, use DecimalPlaces to convert
+	Amount        int64  `json:"amount"`
+	DecimalPlaces int64  `json:"decimalPlaces"`
+	Currency      string `json:"currency"`
+}
+
+type ModerationAction struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string `json:"broadcasterLogin"`
+	BroadcasterName  string `json:"broadcasterName"`
+	// SourceBroadcaster is set when action was made in a shared chat
+	SourceBroadcasterID   string `json:"sourceBroadcasterId,omitempty"`
+	SourceBroadcasterName string `json:"sourceBroadcasterName,omitempty"`
+	ModeratorID           string `json:"moderatorId"`
+	ModeratorLogin        string `json:"moderatorLogin"`
+	ModeratorName         string `json:"moderatorName"`
+	// Action is twitch action name, e.g. ban, timeout, delete, slow, warn
+	Action      string     `json:"action"`
+	TargetID    string     `json:"targetId,omitempty"`
+	TargetLogin string     `json:"targetLogin,omitempty"`
+	TargetName  string     `json:"targetName,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	MessageID   string     `json:"messageId,omitempty"`
+	MessageBody string     `json:"messageBody,omitempty"`
+	// Details is raw action payload from twitch
+	Details json.RawMessage `json:"details,omitempty"`
+}
+
+type MessageDelete struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string `json:"broadcasterLogin"`
+	BroadcasterName  string `json:"broadcasterName"`
+	// MessageID is the id of the deleted events.Message
+	MessageID    string `json:"messageId"`
+	ChatterID    string `json:"chatterId"`
+	ChatterLogin string `json:"chatterLogin"`
+	ChatterName  string `json:"chatterName"`
+}
+
+type ChatClear struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string `json:"broadcasterLogin"`
+	BroadcasterName  string `json:"broadcasterName"`
+}
+
+// ChatClearUserMessages is sent when user is banned or timed out and all of
+// their messages are removed.
+type ChatClearUserMessages struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string `json:"broadcasterLogin"`
+	BroadcasterName  string `json:"broadcasterName"`
+	ChatterID        string `json:"chatterId"`
+	ChatterLogin     string `json:"chatterLogin"`
+	ChatterName      string `json:"chatterName"`
+}
+
+type ChannelUpdate struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin            string   `json:"broadcasterLogin"`
+	BroadcasterName             string   `json:"broadcasterName"`
+	Title                       string   `json:"title"`
+	Language                    string   `json:"language"`
+	CategoryID                  string   `json:"categoryId"`
+	CategoryName                string   `json:"categoryName"`
+	ContentClassificationLabels []string `json:"contentClassificationLabels"`
+}
+
+type AdBreakBegin struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string `json:"broadcasterLogin"`
+	BroadcasterName  string `json:"broadcasterName"`
+	// Duration in seconds
+	Duration    int  `json:"duration"`
+	IsAutomatic bool `json:"isAutomatic"`
+	// Requester is the broadcaster for automatic ads
+	RequesterID    string    `json:"requesterId"`
+	RequesterLogin string    `json:"requesterLogin"`
+	RequesterName  string    `json:"requesterName"`
+	StartedAt      time.Time `json:"startedAt"`
+}
+
+// AutomodMessage is a message held by automod, moderator and status are set
+// only when the message was approved, denied or expired.
+type AutomodMessage struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin string    `json:"broadcasterLogin"`
+	BroadcasterName  string    `json:"broadcasterName"`
+	MessageID        string    `json:"messageId"`
+	Message          string    `json:"message"`
+	ChatterID        string    `json:"chatterId"`
+	ChatterLogin     string    `json:"chatterLogin"`
+	ChatterName      string    `json:"chatterName"`
+	Category         string    `json:"category"`
+	Level            int       `json:"level"`
+	HeldAt           time.Time `json:"heldAt"`
+	ModeratorID      string    `json:"moderatorId,omitempty"`
+	ModeratorLogin   string    `json:"moderatorLogin,omitempty"`
+	ModeratorName    string    `json:"moderatorName,omitempty"`
+	// Status is one of Approved, Denied or Expired
+	Status string `json:"status,omitempty"`
+}
+
+type AutomodMessageModerate struct {
+	sharedEvents.EventCommon
+
+	MessageID string `json:"messageId"`
+	// Allow approves the message, otherwise it is denied
+	Allow bool `json:"allow"`
+}
+
+type SharedChatSession struct {
+	sharedEvents.EventCommon
+
+	BroadcasterLogin     string                  `json:"broadcasterLogin"`
+	BroadcasterName      string                  `json:"broadcasterName"`
+	SessionID            string                  `json:"sessionId"`
+	HostBroadcasterID    string                  `json:"hostBroadcasterId"`
+	HostBroadcasterLogin string                  `json:"hostBroadcasterLogin"`
+	HostBroadcasterName  string                  `json:"hostBroadcasterName"`
+	Participants         []SharedChatParticipant `json:"participants,omitempty"`
+}
+
+type SharedChatParticipant struct {
+	BroadcasterID    string `json:"broadcasterId"`
+	BroadcasterLogin string `json:"broadcasterLogin"`
+	BroadcasterName  string `json:"broadcasterName"`
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/arnokay/arnobot-shared/pkg/assert"
+	"github.com/arnokay/arnobot-shared/platform"
+	sharedTopics "github.com/arnokay/arnobot-shared/topics"
+	"github.com/nats-io/nats.go"
+
+	"github.com/arnokay/arnobot-twitch/internal/data"
+	"github.com/arnokay/arnobot-twitch/internal/service"
+	"github.com/arnokay/arnobot-twitch/internal/topics"
+)
+
+type AdController struct {
+	twitchService *service.TwitchService
+
+	logger applog.Logger
+}
+
+func NewAdController(
+	twitchService *service.TwitchService,
+) *AdController {
+	logger := applog.NewServiceLogger("mb-ad-controller")
+
+	return &AdController{
+		twitchService: twitchService,
+
+		logger: logger,
+	}
+}
+
+func (c *AdController) Connect(conn *nats.Conn) {
+	subscriptions := []struct {
+		topic   string
+		handler nats.MsgHandler
+	}{
+		{topics.PlatformBroadcasterAdStart, c.AdStart},
+		{topics.PlatformBroadcasterAdScheduleGet, c.AdScheduleGet},
+		{topics.PlatformBroadcasterAdSnooze, c.AdSnooze},
+	}
+
+	for _, sub := range subscriptions {
+		topic := sharedTopics.
+			TopicBuilder(sub.topic).
+			Platform(platform.Twitch).
+			BroadcasterID(sharedTopics.Any).
+			Build()
+		_, err := conn.QueueSubscribe(topic, topic, sub.handler)
+		assert.NoError(err, fmt.Sprintf("MBAdController cannot subscribe to the topic: %s", topic))
+	}
+}
+
+func (c *AdController) AdStart(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.AdStart) string { return arg.BroadcasterID }, c.twitchService.AdStart)
+}
+
+func (c *AdController) AdScheduleGet(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.AdScheduleGet) string { return arg.BroadcasterID }, c.twitchService.AdScheduleGet)
+}
+
+func (c *AdController) AdSnooze(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.AdSnooze) string { return arg.BroadcasterID }, c.twitchService.AdSnooze)
+}
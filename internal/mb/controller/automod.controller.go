@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/arnokay/arnobot-shared/pkg/assert"
+	"github.com/arnokay/arnobot-shared/platform"
+	sharedTopics "github.com/arnokay/arnobot-shared/topics"
+	"github.com/nats-io/nats.go"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+	"github.com/arnokay/arnobot-twitch/internal/service"
+	"github.com/arnokay/arnobot-twitch/internal/topics"
+)
+
+type AutomodController struct {
+	twitchService *service.TwitchService
+	botService    *service.BotService
+
+	logger applog.Logger
+}
+
+func NewAutomodController(
+	twitchService *service.TwitchService,
+	botService *service.BotService,
+) *AutomodController {
+	logger := applog.NewServiceLogger("mb-automod-controller")
+
+	return &AutomodController{
+		twitchService: twitchService,
+		botService:    botService,
+
+		logger: logger,
+	}
+}
+
+func (c *AutomodController) Connect(conn *nats.Conn) {
+	topic := sharedTopics.
+		TopicBuilder(topics.PlatformBroadcasterAutomodMessageModerate).
+		Platform(platform.Twitch).
+		BroadcasterID(sharedTopics.Any).
+		Build()
+	_, err := conn.QueueSubscribe(topic, topic, c.AutomodMessageModerate)
+	assert.NoError(err, fmt.Sprintf("MBAutomodController cannot subscribe to the topic: %s", topic))
+}
+
+func (c *AutomodController) AutomodMessageModerate(msg *nats.Msg) {
+	handleRequest(msg, func(ctx context.Context, arg events.AutomodMessageModerate) (bool, error) {
+		err := verifyBroadcaster(ctx, c.botService, msg.Subject, arg.EventCommon)
+		if err != nil {
+			return false, err
+		}
+
+		err = c.twitchService.BotModerateHeldMessage(ctx, arg.BotID, arg.MessageID, arg.Allow)
+		return err == nil, err
+	})
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/arnokay/arnobot-shared/pkg/assert"
+	"github.com/arnokay/arnobot-shared/platform"
+	sharedTopics "github.com/arnokay/arnobot-shared/topics"
+	"github.com/nats-io/nats.go"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+	"github.com/arnokay/arnobot-twitch/internal/service"
+	"github.com/arnokay/arnobot-twitch/internal/topics"
+)
+
+type ChannelController struct {
+	twitchService *service.TwitchService
+	botService    *service.BotService
+
+	logger applog.Logger
+}
+
+func NewChannelController(
+	twitchService *service.TwitchService,
+	botService *service.BotService,
+) *ChannelController {
+	logger := applog.NewServiceLogger("mb-channel-controller")
+
+	return &ChannelController{
+		twitchService: twitchService,
+		botService:    botService,
+
+		logger: logger,
+	}
+}
+
+func (c *ChannelController) Connect(conn *nats.Conn) {
+	topic := sharedTopics.
+		TopicBuilder(topics.PlatformBroadcasterShoutoutSend).
+		Platform(platform.Twitch).
+		BroadcasterID(sharedTopics.Any).
+		Build()
+	_, err := conn.QueueSubscribe(topic, topic, c.ShoutoutSend)
+	assert.NoError(err, fmt.Sprintf("MBChannelController cannot subscribe to the topic: %s", topic))
+}
+
+func (c *ChannelController) ShoutoutSend(msg *nats.Msg) {
+	handleRequest(msg, func(ctx context.Context, arg events.ShoutoutSend) (bool, error) {
+		err := verifyBroadcaster(ctx, c.botService, msg.Subject, arg.EventCommon)
+		if err != nil {
+			return false, err
+		}
+
+		err = c.twitchService.BotSendShoutout(ctx, arg.BotID, arg.BroadcasterID, arg.ToBroadcasterID)
+		return err == nil, err
+	})
+}
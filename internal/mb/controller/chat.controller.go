@@ -1,8 +1,9 @@
 package controller
 
 import (
+	"context"
 	"fmt"
-	
+	"time"
 
 	"github.com/arnokay/arnobot-shared/applog"
 	"github.com/arnokay/arnobot-shared/apptype"
@@ -12,22 +13,35 @@ import (
 	"github.com/arnokay/arnobot-shared/topics"
 	"github.com/nats-io/nats.go"
 
+	"github.com/arnokay/arnobot-twitch/internal/config"
+	"github.com/arnokay/arnobot-twitch/internal/data"
 	"github.com/arnokay/arnobot-twitch/internal/service"
 )
 
 type ChatController struct {
-	twitchService *service.TwitchService
+	twitchService     *service.TwitchService
+	deadLetterService *service.DeadLetterService
+
+	maxAttempts int
+	backoff     []time.Duration
 
 	logger applog.Logger
 }
 
 func NewChatController(
 	twitchService *service.TwitchService,
+	deadLetterService *service.DeadLetterService,
 ) *ChatController {
 	logger := applog.NewServiceLogger("mb-chat-controller")
 
+	backoff, err := config.ParseBackoff(config.Config.ChatSend.Backoff)
+	assert.NoError(err, "chat send: cannot parse backoff")
+
 	return &ChatController{
-		twitchService: twitchService,
+		twitchService:     twitchService,
+		deadLetterService: deadLetterService,
+		maxAttempts:       config.Config.ChatSend.MaxAttempts,
+		backoff:           backoff,
 
 		logger: logger,
 	}
@@ -55,13 +69,53 @@ func (c *ChatController) ChatMessageSend(msg *nats.Msg) {
 	ctx, cancel := newControllerContext(payload.TraceID)
 	defer cancel()
 
-	err := c.twitchService.AppSendChannelMessage(ctx, payload.Data.BotID, payload.Data.BroadcasterID, payload.Data.Message, payload.Data.ReplyTo)
+	// retries block the subscription, so messages after this one are not
+	// sent before it
+	attempts, err := c.sendChannelMessage(ctx, payload.Data)
 	if err != nil {
 		c.logger.ErrorContext(
 			ctx,
 			"cannot send message to channel",
+			"err", err,
+			"attempts", attempts,
 			"payload", payload,
 		)
+		dlqErr := c.deadLetterService.Create(ctx, data.DeadLetterCreate{
+			Kind:     data.DeadLetterKindChatSend,
+			Subject:  msg.Subject,
+			Header:   msg.Header,
+			Body:     msg.Data,
+			Err:      err,
+			Attempts: attempts,
+		})
+		if dlqErr != nil {
+			// message is lost at this point
+			c.logger.ErrorContext(
+				ctx,
+				"cannot save message to dead letters",
+				"err", dlqErr,
+				"payload", payload,
+			)
+		}
 		return
 	}
 }
+
+// sendChannelMessage sends message with retries, it returns number of attempts
+// and the last error.
+func (c *ChatController) sendChannelMessage(ctx context.Context, arg events.MessageSend) (int, error) {
+	for attempt := 1; ; attempt++ {
+		err := c.twitchService.AppSendChannelMessage(ctx, arg.BotID, arg.BroadcasterID, arg.Message, arg.ReplyTo)
+		if err == nil || attempt >= c.maxAttempts || service.IsPermanentErr(err) {
+			return attempt, err
+		}
+
+		delay := c.backoff[min(attempt, len(c.backoff))-1]
+		c.logger.WarnContext(ctx, "cannot send message to channel, will retry", "err", err, "attempt", attempt, "delay", delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return attempt, err
+		}
+	}
+}
@@ -2,22 +2,65 @@ package controller
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/arnokay/arnobot-shared/apperror"
 	"github.com/arnokay/arnobot-shared/apptype"
+	sharedEvents "github.com/arnokay/arnobot-shared/events"
 	"github.com/arnokay/arnobot-shared/trace"
 	"github.com/nats-io/nats.go"
+
+	"github.com/arnokay/arnobot-twitch/internal/service"
 )
 
 type Controllers struct {
-	ChatController *ChatController
-	BotController  *BotController
+	ChatController       *ChatController
+	BotController        *BotController
+	ChannelController    *ChannelController
+	RewardController     *RewardController
+	PollController       *PollController
+	PredictionController *PredictionController
+	AdController         *AdController
+	AutomodController    *AutomodController
+	DeadLetterController *DeadLetterController
 }
 
 func (c *Controllers) Connect(conn *nats.Conn) {
 	c.ChatController.Connect(conn)
 	c.BotController.Connect(conn)
+	c.ChannelController.Connect(conn)
+	c.RewardController.Connect(conn)
+	c.PollController.Connect(conn)
+	c.PredictionController.Connect(conn)
+	c.AdController.Connect(conn)
+	c.AutomodController.Connect(conn)
+	c.DeadLetterController.Connect(conn)
+}
+
+// verifyBroadcaster rejects request whose payload targets other broadcaster
+// than its topic, or a bot that is not selected for the broadcaster.
+func verifyBroadcaster(
+	ctx context.Context,
+	botService *service.BotService,
+	subject string,
+	common sharedEvents.EventCommon,
+) error {
+	err := verifyTopicBroadcaster(subject, common.BroadcasterID)
+	if err != nil {
+		return err
+	}
+
+	broadcasterID := common.BroadcasterID
+	selectedBot, err := botService.SelectedBotGetByBroadcasterID(ctx, broadcasterID)
+	if err != nil {
+		return err
+	}
+	if selectedBot.BotID != common.BotID {
+		return apperror.New(apperror.CodeForbidden, "bot is not selected for the broadcaster", nil)
+	}
+
+	return nil
 }
 
 func newControllerContext(traceID string) (context.Context, context.CancelFunc) {
@@ -70,3 +113,30 @@ func handlePublish[TReq any](
 
 	handler(ctx, payload.Data)
 }
+
+// verifyTopicBroadcaster rejects request whose payload targets other
+// broadcaster than its topic.
+func verifyTopicBroadcaster(subject string, broadcasterID string) error {
+	if broadcasterID != subject[strings.LastIndex(subject, ".")+1:] {
+		return apperror.New(apperror.CodeInvalidInput, "broadcaster does not match the topic", nil)
+	}
+
+	return nil
+}
+
+// handleBroadcasterRequest is handleRequest for request that acts with token
+// of the payload broadcaster, so the broadcaster has to match the topic.
+func handleBroadcasterRequest[TReq, TResp any](
+	msg *nats.Msg,
+	broadcasterID func(TReq) string,
+	fn func(context.Context, TReq) (TResp, error),
+) {
+	handleRequest(msg, func(ctx context.Context, arg TReq) (TResp, error) {
+		err := verifyTopicBroadcaster(msg.Subject, broadcasterID(arg))
+		if err != nil {
+			return *new(TResp), err
+		}
+
+		return fn(ctx, arg)
+	})
+}
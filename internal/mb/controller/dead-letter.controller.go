@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/arnokay/arnobot-shared/pkg/assert"
+	"github.com/arnokay/arnobot-shared/platform"
+	sharedTopics "github.com/arnokay/arnobot-shared/topics"
+	"github.com/nats-io/nats.go"
+
+	"github.com/arnokay/arnobot-twitch/internal/data"
+	"github.com/arnokay/arnobot-twitch/internal/service"
+	"github.com/arnokay/arnobot-twitch/internal/topics"
+)
+
+type DeadLetterController struct {
+	deadLetterService *service.DeadLetterService
+
+	logger applog.Logger
+}
+
+func NewDeadLetterController(
+	deadLetterService *service.DeadLetterService,
+) *DeadLetterController {
+	logger := applog.NewServiceLogger("mb-dead-letter-controller")
+
+	return &DeadLetterController{
+		deadLetterService: deadLetterService,
+
+		logger: logger,
+	}
+}
+
+func (c *DeadLetterController) Connect(conn *nats.Conn) {
+	subscriptions := []struct {
+		topic   string
+		handler nats.MsgHandler
+	}{
+		{topics.PlatformDeadLetterList, c.DeadLetterList},
+		{topics.PlatformDeadLetterGet, c.DeadLetterGet},
+		{topics.PlatformDeadLetterReplay, c.DeadLetterReplay},
+		{topics.PlatformDeadLetterPurge, c.DeadLetterPurge},
+	}
+
+	for _, sub := range subscriptions {
+		topic := sharedTopics.
+			TopicBuilder(sub.topic).
+			Platform(platform.Twitch).
+			Build()
+		_, err := conn.QueueSubscribe(topic, topic, sub.handler)
+		assert.NoError(err, fmt.Sprintf("MBDeadLetterController cannot subscribe to the topic: %s", topic))
+	}
+}
+
+func (c *DeadLetterController) DeadLetterList(msg *nats.Msg) {
+	handleRequest(msg, c.deadLetterService.List)
+}
+
+func (c *DeadLetterController) DeadLetterGet(msg *nats.Msg) {
+	handleRequest(msg, c.deadLetterService.Get)
+}
+
+func (c *DeadLetterController) DeadLetterReplay(msg *nats.Msg) {
+	handleRequest(msg, c.deadLetterService.Replay)
+}
+
+func (c *DeadLetterController) DeadLetterPurge(msg *nats.Msg) {
+	handleRequest(msg, func(ctx context.Context, arg data.DeadLetterPurge) (bool, error) {
+		err := c.deadLetterService.Purge(ctx, arg)
+		return err == nil, err
+	})
+}
@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/arnokay/arnobot-shared/pkg/assert"
+	"github.com/arnokay/arnobot-shared/platform"
+	sharedTopics "github.com/arnokay/arnobot-shared/topics"
+	"github.com/nats-io/nats.go"
+
+	"github.com/arnokay/arnobot-twitch/internal/data"
+	"github.com/arnokay/arnobot-twitch/internal/service"
+	"github.com/arnokay/arnobot-twitch/internal/topics"
+)
+
+type PollController struct {
+	twitchService *service.TwitchService
+
+	logger applog.Logger
+}
+
+func NewPollController(
+	twitchService *service.TwitchService,
+) *PollController {
+	logger := applog.NewServiceLogger("mb-poll-controller")
+
+	return &PollController{
+		twitchService: twitchService,
+
+		logger: logger,
+	}
+}
+
+func (c *PollController) Connect(conn *nats.Conn) {
+	subscriptions := []struct {
+		topic   string
+		handler nats.MsgHandler
+	}{
+		{topics.PlatformBroadcasterPollCreate, c.PollCreate},
+		{topics.PlatformBroadcasterPollEnd, c.PollEnd},
+	}
+
+	for _, sub := range subscriptions {
+		topic := sharedTopics.
+			TopicBuilder(sub.topic).
+			Platform(platform.Twitch).
+			BroadcasterID(sharedTopics.Any).
+			Build()
+		_, err := conn.QueueSubscribe(topic, topic, sub.handler)
+		assert.NoError(err, fmt.Sprintf("MBPollController cannot subscribe to the topic: %s", topic))
+	}
+}
+
+func (c *PollController) PollCreate(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.PollCreate) string { return arg.BroadcasterID }, c.twitchService.PollCreate)
+}
+
+func (c *PollController) PollEnd(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.PollEnd) string { return arg.BroadcasterID }, c.twitchService.PollEnd)
+}
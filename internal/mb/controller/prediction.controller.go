@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/arnokay/arnobot-shared/pkg/assert"
+	"github.com/arnokay/arnobot-shared/platform"
+	sharedTopics "github.com/arnokay/arnobot-shared/topics"
+	"github.com/nats-io/nats.go"
+
+	"github.com/arnokay/arnobot-twitch/internal/data"
+	"github.com/arnokay/arnobot-twitch/internal/service"
+	"github.com/arnokay/arnobot-twitch/internal/topics"
+)
+
+type PredictionController struct {
+	twitchService *service.TwitchService
+
+	logger applog.Logger
+}
+
+func NewPredictionController(
+	twitchService *service.TwitchService,
+) *PredictionController {
+	logger := applog.NewServiceLogger("mb-prediction-controller")
+
+	return &PredictionController{
+		twitchService: twitchService,
+
+		logger: logger,
+	}
+}
+
+func (c *PredictionController) Connect(conn *nats.Conn) {
+	subscriptions := []struct {
+		topic   string
+		handler nats.MsgHandler
+	}{
+		{topics.PlatformBroadcasterPredictionCreate, c.PredictionCreate},
+		{topics.PlatformBroadcasterPredictionLock, c.PredictionLock},
+		{topics.PlatformBroadcasterPredictionResolve, c.PredictionResolve},
+		{topics.PlatformBroadcasterPredictionCancel, c.PredictionCancel},
+	}
+
+	for _, sub := range subscriptions {
+		topic := sharedTopics.
+			TopicBuilder(sub.topic).
+			Platform(platform.Twitch).
+			BroadcasterID(sharedTopics.Any).
+			Build()
+		_, err := conn.QueueSubscribe(topic, topic, sub.handler)
+		assert.NoError(err, fmt.Sprintf("MBPredictionController cannot subscribe to the topic: %s", topic))
+	}
+}
+
+func (c *PredictionController) PredictionCreate(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.PredictionCreate) string { return arg.BroadcasterID }, c.twitchService.PredictionCreate)
+}
+
+func (c *PredictionController) PredictionLock(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.PredictionLock) string { return arg.BroadcasterID }, c.twitchService.PredictionLock)
+}
+
+func (c *PredictionController) PredictionResolve(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.PredictionResolve) string { return arg.BroadcasterID }, c.twitchService.PredictionResolve)
+}
+
+func (c *PredictionController) PredictionCancel(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.PredictionCancel) string { return arg.BroadcasterID }, c.twitchService.PredictionCancel)
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/arnokay/arnobot-shared/pkg/assert"
+	"github.com/arnokay/arnobot-shared/platform"
+	sharedTopics "github.com/arnokay/arnobot-shared/topics"
+	"github.com/nats-io/nats.go"
+
+	"github.com/arnokay/arnobot-twitch/internal/data"
+	"github.com/arnokay/arnobot-twitch/internal/service"
+	"github.com/arnokay/arnobot-twitch/internal/topics"
+)
+
+type RewardController struct {
+	twitchService *service.TwitchService
+
+	logger applog.Logger
+}
+
+func NewRewardController(
+	twitchService *service.TwitchService,
+) *RewardController {
+	logger := applog.NewServiceLogger("mb-reward-controller")
+
+	return &RewardController{
+		twitchService: twitchService,
+
+		logger: logger,
+	}
+}
+
+func (c *RewardController) Connect(conn *nats.Conn) {
+	subscriptions := []struct {
+		topic   string
+		handler nats.MsgHandler
+	}{
+		{topics.PlatformBroadcasterRewardRedemptionStatusUpdate, c.RedemptionStatusUpdate},
+		{topics.PlatformBroadcasterRewardCreate, c.RewardCreate},
+		{topics.PlatformBroadcasterRewardUpdate, c.RewardUpdate},
+		{topics.PlatformBroadcasterRewardDelete, c.RewardDelete},
+		{topics.PlatformBroadcasterRewardList, c.RewardList},
+	}
+
+	for _, sub := range subscriptions {
+		topic := sharedTopics.
+			TopicBuilder(sub.topic).
+			Platform(platform.Twitch).
+			BroadcasterID(sharedTopics.Any).
+			Build()
+		_, err := conn.QueueSubscribe(topic, topic, sub.handler)
+		assert.NoError(err, fmt.Sprintf("MBRewardController cannot subscribe to the topic: %s", topic))
+	}
+}
+
+func (c *RewardController) RedemptionStatusUpdate(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.RewardRedemptionStatusUpdate) string { return arg.BroadcasterID }, c.twitchService.RewardRedemptionStatusUpdate)
+}
+
+func (c *RewardController) RewardCreate(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.RewardCreate) string { return arg.BroadcasterID }, c.twitchService.RewardCreate)
+}
+
+func (c *RewardController) RewardUpdate(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.RewardUpdate) string { return arg.BroadcasterID }, c.twitchService.RewardUpdate)
+}
+
+func (c *RewardController) RewardDelete(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.RewardDelete) string { return arg.BroadcasterID }, c.twitchService.RewardDelete)
+}
+
+func (c *RewardController) RewardList(msg *nats.Msg) {
+	handleBroadcasterRequest(msg, func(arg data.RewardsGet) string { return arg.BroadcasterID }, c.twitchService.RewardsGet)
+}
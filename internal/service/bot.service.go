@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"errors"
+	"slices"
 
 	"github.com/arnokay/arnobot-shared/apperror"
 	"github.com/arnokay/arnobot-shared/applog"
@@ -13,6 +15,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/arnokay/arnobot-twitch/internal/dbtransform"
+	"github.com/arnokay/arnobot-twitch/internal/twitchdb"
 )
 
 type BotService struct {
@@ -80,6 +83,11 @@ func (s *BotService) StartBot(ctx context.Context, arg data.PlatformBotToggle) e
 		return err
 	}
 
+	err = s.WebsocketCheckCapacity(ctx, selectedBot.BroadcasterID)
+	if err != nil {
+		return err
+	}
+
 	err = s.whService.SubscribeAll(txCtx, selectedBot.BotID, selectedBot.BroadcasterID)
 	if err != nil {
 		return err
@@ -110,6 +118,77 @@ func (s *BotService) StopBot(ctx context.Context, arg data.PlatformBotToggle) er
 	return nil
 }
 
+func (s *BotService) SelectedBotsEnabledGet(ctx context.Context) ([]data.PlatformSelectedBot, error) {
+	// query is not part of the shared queries yet
+	fromDB, err := twitchdb.New(s.storage.Database(ctx)).TwitchSelectedBotsEnabledGet(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot get enabled selected bots", "err", err)
+		return nil, s.storage.HandleErr(ctx, err)
+	}
+
+	var bots []data.PlatformSelectedBot
+	for _, bot := range fromDB {
+		bots = append(bots, dbtransform.NewPlatformSelectedBotFromTwitchDB(bot))
+	}
+
+	return bots, nil
+}
+
+// WebsocketCheckCapacity checks that enabled bots, including the one in
+// broadcasterID channel, fit into eventsub websocket session. broadcasterID
+// can be empty.
+func (s *BotService) WebsocketCheckCapacity(ctx context.Context, broadcasterID string) error {
+	if s.whService.websocket == nil {
+		return nil
+	}
+
+	bots, err := s.SelectedBotsEnabledGet(ctx)
+	if err != nil {
+		return err
+	}
+
+	channels := len(bots)
+	if broadcasterID != "" && !slices.ContainsFunc(bots, func(bot data.PlatformSelectedBot) bool {
+		return bot.BroadcasterID == broadcasterID
+	}) {
+		channels++
+	}
+
+	return s.whService.WebsocketCheckCapacity(channels)
+}
+
+// SelectedBotsResubscribe subscribes all enabled bots again, websocket
+// subscriptions don't outlive their session.
+func (s *BotService) SelectedBotsResubscribe(ctx context.Context) error {
+	bots, err := s.SelectedBotsEnabledGet(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = s.whService.WebsocketCheckCapacity(len(bots))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "enabled bots don't fit into eventsub websocket session", "err", err)
+		return err
+	}
+
+	var errs []error
+	for _, bot := range bots {
+		err := s.whService.SubscribeAll(ctx, bot.BotID, bot.BroadcasterID)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		s.logger.ErrorContext(ctx, "cannot resubscribe some bots", "failed_count", len(errs), "total", len(bots))
+		return apperror.New(apperror.CodeExternal, "cannot resubscribe some bots", errors.Join(errs...))
+	}
+
+	s.logger.InfoContext(ctx, "resubscribed enabled bots", "total", len(bots))
+
+	return nil
+}
+
 func (s *BotService) SelectedBotSetDefault(ctx context.Context, userID uuid.UUID) (data.PlatformSelectedBot, error) {
 	var bot data.PlatformBot
 
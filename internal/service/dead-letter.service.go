@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/arnokay/arnobot-shared/apperror"
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/arnokay/arnobot-shared/pkg/assert"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/arnokay/arnobot-twitch/internal/config"
+	"github.com/arnokay/arnobot-twitch/internal/data"
+)
+
+const (
+	DeadLetterStream  = "TWITCH_DLQ"
+	DeadLetterSubject = "twitch.dlq"
+
+	HeaderDeadLetterSubject  = "Dlq-Subject"
+	HeaderDeadLetterError    = "Dlq-Error"
+	HeaderDeadLetterAttempts = "Dlq-Attempts"
+	HeaderDeadLetterFailedAt = "Dlq-Failed-At"
+
+	deadLetterListLimit = 50
+)
+
+// DeadLetterService keeps messages that failed all attempts in a jetstream
+// stream, so they can be inspected and replayed later.
+type DeadLetterService struct {
+	nc     *nats.Conn
+	js     jetstream.JetStream
+	stream jetstream.Stream
+
+	logger applog.Logger
+}
+
+func NewDeadLetterService(
+	ctx context.Context,
+	nc *nats.Conn,
+	js jetstream.JetStream,
+) *DeadLetterService {
+	logger := applog.NewServiceLogger("dead-letter-service")
+
+	maxAge, err := time.ParseDuration(config.Config.Ingest.DLQMaxAge)
+	assert.NoError(err, "dead letter: cannot parse max age")
+
+	stream, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     DeadLetterStream,
+		Subjects: []string{DeadLetterSubject + ".>"},
+		MaxAge:   maxAge,
+	})
+	assert.NoError(err, "dead letter: cannot create stream")
+
+	return &DeadLetterService{
+		nc:     nc,
+		js:     js,
+		stream: stream,
+		logger: logger,
+	}
+}
+
+func deadLetterSubject(kind data.DeadLetterKind) string {
+	if kind == "" {
+		return DeadLetterSubject + ".>"
+	}
+	return DeadLetterSubject + "." + string(kind)
+}
+
+func (s *DeadLetterService) Create(ctx context.Context, arg data.DeadLetterCreate) error {
+	msg := nats.NewMsg(deadLetterSubject(arg.Kind))
+	msg.Data = arg.Body
+	for key, values := range arg.Header {
+		// dead letter has to be stored even if the same message was
+		// dead lettered before
+		if key == jetstream.MsgIDHeader {
+			continue
+		}
+		msg.Header[key] = values
+	}
+	msg.Header.Set(HeaderDeadLetterSubject, arg.Subject)
+	msg.Header.Set(HeaderDeadLetterError, arg.Err.Error())
+	msg.Header.Set(HeaderDeadLetterAttempts, strconv.Itoa(arg.Attempts))
+	msg.Header.Set(HeaderDeadLetterFailedAt, time.Now().UTC().Format(time.RFC3339))
+
+	_, err := s.js.PublishMsg(ctx, msg)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot create dead letter", "err", err, "kind", arg.Kind, "cause", arg.Err)
+		return apperror.ErrInternal
+	}
+
+	s.logger.ErrorContext(ctx, "message is dead lettered", "err", arg.Err, "kind", arg.Kind, "attempts", arg.Attempts)
+
+	return nil
+}
+
+func (s *DeadLetterService) List(ctx context.Context, arg data.DeadLetterList) ([]data.DeadLetter, error) {
+	limit := arg.Limit
+	if limit <= 0 {
+		limit = deadLetterListLimit
+	}
+
+	result := []data.DeadLetter{}
+	seq := arg.After + 1
+	for len(result) < limit {
+		msg, err := s.stream.GetMsg(ctx, seq, jetstream.WithGetMsgSubject(deadLetterSubject(arg.Kind)))
+		if err != nil {
+			if errors.Is(err, jetstream.ErrMsgNotFound) {
+				break
+			}
+			s.logger.ErrorContext(ctx, "cannot list dead letters", "err", err, "seq", seq)
+			return nil, apperror.ErrInternal
+		}
+
+		deadLetter := newDeadLetter(msg)
+		// list is for overview, body is returned by Get
+		deadLetter.Body = nil
+		result = append(result, deadLetter)
+		seq = msg.Sequence + 1
+	}
+
+	return result, nil
+}
+
+func (s *DeadLetterService) Get(ctx context.Context, arg data.DeadLetterGet) (data.DeadLetter, error) {
+	msg, err := s.stream.GetMsg(ctx, arg.Seq)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			return data.DeadLetter{}, apperror.ErrNotFound
+		}
+		s.logger.ErrorContext(ctx, "cannot get dead letter", "err", err, "seq", arg.Seq)
+		return data.DeadLetter{}, apperror.ErrInternal
+	}
+
+	return newDeadLetter(msg), nil
+}
+
+// Replay publishes dead letter to its original subject and removes it from
+// the dead letter stream.
+func (s *DeadLetterService) Replay(ctx context.Context, arg data.DeadLetterReplay) (data.DeadLetter, error) {
+	msg, err := s.stream.GetMsg(ctx, arg.Seq)
+	if err != nil {
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			return data.DeadLetter{}, apperror.ErrNotFound
+		}
+		s.logger.ErrorContext(ctx, "cannot get dead letter", "err", err, "seq", arg.Seq)
+		return data.DeadLetter{}, apperror.ErrInternal
+	}
+	deadLetter := newDeadLetter(msg)
+
+	replay := nats.NewMsg(deadLetter.Subject)
+	replay.Data = msg.Data
+	for key, values := range msg.Header {
+		switch key {
+		case HeaderDeadLetterSubject, HeaderDeadLetterError, HeaderDeadLetterAttempts, HeaderDeadLetterFailedAt:
+			continue
+		}
+		replay.Header[key] = values
+	}
+
+	switch deadLetter.Kind {
+	case data.DeadLetterKindEventSub:
+		// replay of the same dead letter is deduplicated by ingest stream
+		replay.Header.Set(jetstream.MsgIDHeader, "dlq.replay."+strconv.FormatUint(arg.Seq, 10))
+		_, err = s.js.PublishMsg(ctx, replay)
+	default:
+		err = s.nc.PublishMsg(replay)
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot replay dead letter", "err", err, "seq", arg.Seq)
+		return data.DeadLetter{}, apperror.ErrInternal
+	}
+
+	err = s.stream.DeleteMsg(ctx, arg.Seq)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot delete replayed dead letter", "err", err, "seq", arg.Seq)
+		return data.DeadLetter{}, apperror.ErrInternal
+	}
+
+	s.logger.InfoContext(ctx, "dead letter replayed", "seq", arg.Seq, "kind", deadLetter.Kind)
+
+	return deadLetter, nil
+}
+
+func (s *DeadLetterService) Purge(ctx context.Context, arg data.DeadLetterPurge) error {
+	var err error
+	if arg.Seq != 0 {
+		err = s.stream.DeleteMsg(ctx, arg.Seq)
+		if errors.Is(err, jetstream.ErrMsgNotFound) {
+			return apperror.ErrNotFound
+		}
+	} else if arg.Kind != "" {
+		err = s.stream.Purge(ctx, jetstream.WithPurgeSubject(deadLetterSubject(arg.Kind)))
+	} else if arg.All {
+		err = s.stream.Purge(ctx)
+	} else {
+		return apperror.ErrInvalidInput
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot purge dead letters", "err", err, "seq", arg.Seq, "kind", arg.Kind)
+		return apperror.ErrInternal
+	}
+
+	return nil
+}
+
+func newDeadLetter(msg *jetstream.RawStreamMsg) data.DeadLetter {
+	attempts, _ := strconv.Atoi(msg.Header.Get(HeaderDeadLetterAttempts))
+	failedAt, _ := time.Parse(time.RFC3339, msg.Header.Get(HeaderDeadLetterFailedAt))
+
+	return data.DeadLetter{
+		Seq:              msg.Sequence,
+		Kind:             data.DeadLetterKind(msg.Subject[len(DeadLetterSubject)+1:]),
+		Subject:          msg.Header.Get(HeaderDeadLetterSubject),
+		MessageID:        msg.Header.Get(HeaderMessageID),
+		SubscriptionType: msg.Header.Get(HeaderSubscriptionType),
+		Error:            msg.Header.Get(HeaderDeadLetterError),
+		Attempts:         attempts,
+		FailedAt:         failedAt,
+		Body:             msg.Data,
+	}
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/arnokay/arnobot-shared/trace"
+	"github.com/coder/websocket"
+
+	"github.com/arnokay/arnobot-twitch/internal/config"
+	"github.com/arnokay/arnobot-twitch/internal/data"
+)
+
+const (
+	eventSubMessageWelcome      = "session_welcome"
+	eventSubMessageKeepalive    = "session_keepalive"
+	eventSubMessageReconnect    = "session_reconnect"
+	eventSubMessageNotification = "notification"
+	eventSubMessageRevocation   = "revocation"
+
+	// twitch may be a bit late with keepalive
+	eventSubKeepaliveGrace = 5 * time.Second
+	eventSubWelcomeTimeout = 10 * time.Second
+	eventSubMaxBackoff     = 30 * time.Second
+	// session that lived less than this is not considered healthy, so backoff
+	// keeps growing, e.g. twitch closes session without subscriptions
+	eventSubStableSession = time.Minute
+
+	// twitch doesn't allow more enabled subscriptions per session
+	eventSubWebsocketMaxSubscriptions = 300
+)
+
+var errEventSubKeepalive = errors.New("eventsub websocket keepalive timeout")
+
+// EventSubNotificationHandler receives notifications in the same shape as
+// webhook callback does.
+type EventSubNotificationHandler func(ctx context.Context, notification data.EventSubNotification) error
+
+// EventSubSessionHandler is called for every new session, subscriptions of
+// previous session are gone by then and have to be created again.
+type EventSubSessionHandler func(ctx context.Context) error
+
+// EventSubWebsocketService receives eventsub notifications over websocket,
+// it is an alternative to webhooks that doesn't need public callback url.
+// Session is reconnected on session_reconnect and whenever keepalive is
+// missed.
+// Single session is limited to 300 subscriptions, see
+// WebhookService.WebsocketCheckCapacity.
+type EventSubWebsocketService struct {
+	url            string
+	readLimit      int64
+	keepaliveGrace time.Duration
+
+	sessionID string
+	mu        sync.RWMutex
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	logger applog.Logger
+}
+
+func NewEventSubWebsocketService() *EventSubWebsocketService {
+	logger := applog.NewServiceLogger("eventsub-websocket-service")
+
+	return &EventSubWebsocketService{
+		url:            config.Config.EventSub.WebsocketURL,
+		readLimit:      config.Config.Webhooks.MaxBodySize,
+		keepaliveGrace: eventSubKeepaliveGrace,
+		logger:         logger,
+	}
+}
+
+// SessionID returns id of the current session, it is empty while there is
+// no session.
+func (s *EventSubWebsocketService) SessionID() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sessionID
+}
+
+func (s *EventSubWebsocketService) setSessionID(sessionID string) {
+	s.mu.Lock()
+	s.sessionID = sessionID
+	s.mu.Unlock()
+}
+
+// Start keeps session open until Stop is called.
+func (s *EventSubWebsocketService) Start(
+	handler EventSubNotificationHandler,
+	onSession EventSubSessionHandler,
+) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx, handler, onSession)
+}
+
+func (s *EventSubWebsocketService) Stop(ctx context.Context) {
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+	}
+}
+
+func (s *EventSubWebsocketService) run(
+	ctx context.Context,
+	handler EventSubNotificationHandler,
+	onSession EventSubSessionHandler,
+) {
+	defer close(s.done)
+
+	backoff := time.Second
+	for {
+		startedAt := time.Now()
+		err := s.session(ctx, handler, onSession)
+		s.setSessionID("")
+		if ctx.Err() != nil {
+			return
+		}
+
+		if time.Since(startedAt) > eventSubStableSession {
+			backoff = time.Second
+		}
+		s.logger.Warn("eventsub websocket session ended, reconnecting", "err", err, "delay", backoff)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff = min(backoff*2, eventSubMaxBackoff)
+	}
+}
+
+type eventSubConn struct {
+	conn     *websocket.Conn
+	session  data.EventSubWebsocketSession
+	messages <-chan eventSubRead
+	cancel   context.CancelFunc
+	grace    time.Duration
+}
+
+func (c *eventSubConn) close(reason string) {
+	c.conn.Close(websocket.StatusNormalClosure, reason)
+	c.cancel()
+}
+
+// closeNow doesn't wait for close handshake, peer is probably gone.
+func (c *eventSubConn) closeNow() {
+	c.conn.CloseNow()
+	c.cancel()
+}
+
+type eventSubRead struct {
+	b   []byte
+	err error
+}
+
+type eventSubReconnect struct {
+	conn *eventSubConn
+	err  error
+}
+
+func (s *EventSubWebsocketService) session(
+	ctx context.Context,
+	handler EventSubNotificationHandler,
+	onSession EventSubSessionHandler,
+) error {
+	conn, err := s.connect(ctx, s.url)
+	if err != nil {
+		return err
+	}
+	s.setSessionID(conn.session.ID)
+	s.logger.Info("eventsub websocket session started", "sessionID", conn.session.ID)
+
+	go func() {
+		sessionCtx := trace.Context(ctx, trace.New())
+		err := onSession(sessionCtx)
+		if err != nil {
+			s.logger.ErrorContext(sessionCtx, "cannot subscribe on new eventsub session", "err", err)
+		}
+	}()
+
+	keepalive := time.NewTimer(conn.keepalive())
+	defer keepalive.Stop()
+
+	var reconnect chan eventSubReconnect
+	defer func() {
+		if reconnect == nil {
+			return
+		}
+		go func(reconnect chan eventSubReconnect) {
+			res := <-reconnect
+			if res.conn != nil {
+				res.conn.close("")
+			}
+		}(reconnect)
+	}()
+
+	// old connection is read after reconnect until twitch closes it, it can
+	// still have notifications that were sent before the new one was welcomed
+	var old *eventSubConn
+	var oldMessages <-chan eventSubRead
+	var oldTimeout <-chan time.Time
+	defer func() {
+		if old != nil {
+			old.closeNow()
+		}
+	}()
+
+	messages := conn.messages
+	for {
+		select {
+		case <-ctx.Done():
+			conn.close("")
+			return ctx.Err()
+		case <-keepalive.C:
+			conn.closeNow()
+			return errEventSubKeepalive
+		case res := <-reconnect:
+			reconnect = nil
+			if res.err != nil {
+				conn.closeNow()
+				return res.err
+			}
+			// twitch moved subscriptions to the new connection
+			if messages != nil {
+				old = conn
+				oldMessages = messages
+				oldTimeout = time.After(eventSubWelcomeTimeout)
+			} else {
+				conn.closeNow()
+			}
+			conn = res.conn
+			messages = conn.messages
+			s.setSessionID(conn.session.ID)
+			keepalive.Reset(conn.keepalive())
+			s.logger.Info("eventsub websocket reconnected", "sessionID", conn.session.ID)
+		case read := <-oldMessages:
+			if read.err != nil {
+				old.closeNow()
+				old, oldMessages, oldTimeout = nil, nil, nil
+				continue
+			}
+			// reconnect of the old connection is already handled
+			s.handleMessage(ctx, read.b, handler)
+		case <-oldTimeout:
+			old.close("reconnected")
+			old, oldMessages, oldTimeout = nil, nil, nil
+		case read := <-messages:
+			if read.err != nil {
+				if reconnect != nil {
+					// old connection can be closed before new one is welcomed
+					messages = nil
+					continue
+				}
+				conn.closeNow()
+				return read.err
+			}
+			keepalive.Reset(conn.keepalive())
+
+			reconnectURL := s.handleMessage(ctx, read.b, handler)
+			if reconnect != nil || reconnectURL == "" {
+				continue
+			}
+
+			// old connection keeps delivering notifications until the new
+			// one is welcomed
+			reconnect = make(chan eventSubReconnect, 1)
+			go func(url string) {
+				c, err := s.connect(ctx, url)
+				reconnect <- eventSubReconnect{conn: c, err: err}
+			}(reconnectURL)
+		}
+	}
+}
+
+// handleMessage passes notifications to handler, it returns reconnect url when
+// twitch asks to reconnect.
+func (s *EventSubWebsocketService) handleMessage(
+	ctx context.Context,
+	b []byte,
+	handler EventSubNotificationHandler,
+) string {
+	var msg data.EventSubWebsocketMessage
+	err := json.Unmarshal(b, &msg)
+	if err != nil {
+		s.logger.Error("cannot parse eventsub websocket message", "err", err)
+		return ""
+	}
+
+	switch msg.Metadata.MessageType {
+	case eventSubMessageKeepalive:
+	case eventSubMessageNotification, eventSubMessageRevocation:
+		msgCtx := trace.Context(ctx, trace.New())
+		err := handler(msgCtx, data.EventSubNotification{
+			MessageID:        msg.Metadata.MessageID,
+			MessageType:      msg.Metadata.MessageType,
+			SubscriptionType: msg.Metadata.SubscriptionType,
+			Body:             msg.Payload,
+		})
+		if err != nil {
+			// twitch doesn't redeliver websocket messages
+			s.logger.ErrorContext(msgCtx, "cannot handle eventsub websocket notification",
+				"err", err,
+				"messageID", msg.Metadata.MessageID,
+				"subType", msg.Metadata.SubscriptionType,
+			)
+		}
+	case eventSubMessageReconnect:
+		var payload struct {
+			Session data.EventSubWebsocketSession `json:"session"`
+		}
+		json.Unmarshal(msg.Payload, &payload)
+		return payload.Session.ReconnectURL
+	default:
+		s.logger.Debug("unknown eventsub websocket message", "type", msg.Metadata.MessageType)
+	}
+
+	return ""
+}
+
+// connect dials url and waits for welcome message.
+func (s *EventSubWebsocketService) connect(ctx context.Context, url string) (*eventSubConn, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, eventSubWelcomeTimeout)
+	defer cancel()
+
+	conn, _, err := websocket.Dial(dialCtx, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot dial eventsub websocket: %w", err)
+	}
+	conn.SetReadLimit(s.readLimit)
+
+	_, b, err := conn.Read(dialCtx)
+	if err != nil {
+		conn.CloseNow()
+		return nil, fmt.Errorf("cannot read eventsub websocket welcome: %w", err)
+	}
+
+	var msg data.EventSubWebsocketMessage
+	var payload struct {
+		Session data.EventSubWebsocketSession `json:"session"`
+	}
+	err = json.Unmarshal(b, &msg)
+	if err == nil {
+		err = json.Unmarshal(msg.Payload, &payload)
+	}
+	if err != nil || msg.Metadata.MessageType != eventSubMessageWelcome {
+		conn.CloseNow()
+		return nil, fmt.Errorf("unexpected eventsub websocket message %q: %v", msg.Metadata.MessageType, err)
+	}
+
+	connCtx, connCancel := context.WithCancel(ctx)
+
+	return &eventSubConn{
+		conn:     conn,
+		session:  payload.Session,
+		messages: s.read(connCtx, conn),
+		cancel:   connCancel,
+		grace:    s.keepaliveGrace,
+	}, nil
+}
+
+func (s *EventSubWebsocketService) read(ctx context.Context, conn *websocket.Conn) <-chan eventSubRead {
+	messages := make(chan eventSubRead)
+
+	go func() {
+		for {
+			_, b, err := conn.Read(ctx)
+			select {
+			case messages <- eventSubRead{b: b, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return messages
+}
+
+func (c *eventSubConn) keepalive() time.Duration {
+	return time.Duration(c.session.KeepaliveTimeoutSeconds)*time.Second + c.grace
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/coder/websocket"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/arnokay/arnobot-twitch/internal/data"
+)
+
+const testTimeout = 5 * time.Second
+
+// fakeEventSub is twitch eventsub websocket server, connections are handed to
+// the test that sends messages on its own.
+type fakeEventSub struct {
+	*httptest.Server
+	conns chan *fakeEventSubConn
+}
+
+type fakeEventSubConn struct {
+	*websocket.Conn
+	// closed is closed when client is gone
+	closed chan struct{}
+}
+
+func newFakeEventSub(t *testing.T) *fakeEventSub {
+	t.Helper()
+
+	f := &fakeEventSub{
+		conns: make(chan *fakeEventSubConn, 4),
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			t.Errorf("cannot accept websocket: %v", err)
+			return
+		}
+
+		fc := &fakeEventSubConn{Conn: conn, closed: make(chan struct{})}
+		f.conns <- fc
+
+		// client doesn't send anything, reading handles close frames
+		for {
+			_, _, err := conn.Read(context.Background())
+			if err != nil {
+				close(fc.closed)
+				return
+			}
+		}
+	}))
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+func (f *fakeEventSub) accept(t *testing.T) *fakeEventSubConn {
+	t.Helper()
+
+	select {
+	case conn := <-f.conns:
+		return conn
+	case <-time.After(testTimeout):
+		t.Fatal("client did not connect")
+		return nil
+	}
+}
+
+func (c *fakeEventSubConn) send(t *testing.T, messageType, messageID, subType string, payload any) {
+	t.Helper()
+
+	b, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"message_id":        messageID,
+			"message_type":      messageType,
+			"message_timestamp": time.Now(),
+			"subscription_type": subType,
+		},
+		"payload": payload,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	err = c.Write(ctx, websocket.MessageText, b)
+	if err != nil {
+		t.Fatalf("cannot write %s: %v", messageType, err)
+	}
+}
+
+func (c *fakeEventSubConn) welcome(t *testing.T, sessionID string, keepaliveSeconds int) {
+	t.Helper()
+
+	c.send(t, eventSubMessageWelcome, "welcome-"+sessionID, "", map[string]any{
+		"session": map[string]any{
+			"id":                        sessionID,
+			"status":                    "connected",
+			"keepalive_timeout_seconds": keepaliveSeconds,
+		},
+	})
+}
+
+func (c *fakeEventSubConn) notification(t *testing.T, messageType, messageID, subType string) {
+	t.Helper()
+
+	c.send(t, messageType, messageID, subType, map[string]any{
+		"subscription": map[string]any{"type": subType},
+	})
+}
+
+func (c *fakeEventSubConn) waitClosed(t *testing.T) {
+	t.Helper()
+
+	select {
+	case <-c.closed:
+	case <-time.After(testTimeout):
+		t.Fatal("client did not close connection")
+	}
+}
+
+func newTestEventSubWebsocketService(t *testing.T, url string) *EventSubWebsocketService {
+	t.Helper()
+
+	s := &EventSubWebsocketService{
+		url:            url,
+		readLimit:      1 << 20,
+		keepaliveGrace: eventSubKeepaliveGrace,
+		logger:         applog.NewServiceLogger("eventsub-websocket-service"),
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+		defer cancel()
+		s.Stop(ctx)
+	})
+
+	return s
+}
+
+// sessionRecorder records session id of every onSession call.
+func sessionRecorder(s *EventSubWebsocketService) (chan string, EventSubSessionHandler) {
+	sessions := make(chan string, 4)
+
+	return sessions, func(ctx context.Context) error {
+		sessions <- s.SessionID()
+		return nil
+	}
+}
+
+func notificationRecorder() (chan data.EventSubNotification, EventSubNotificationHandler) {
+	notifications := make(chan data.EventSubNotification, 8)
+
+	return notifications, func(ctx context.Context, notification data.EventSubNotification) error {
+		notifications <- notification
+		return nil
+	}
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(testTimeout):
+		var zero T
+		t.Fatal("nothing received")
+		return zero
+	}
+}
+
+func TestEventSubWebsocketWelcome(t *testing.T) {
+	srv := newFakeEventSub(t)
+	s := newTestEventSubWebsocketService(t, srv.URL)
+	sessions, onSession := sessionRecorder(s)
+	_, handler := notificationRecorder()
+
+	if s.SessionID() != "" {
+		t.Fatalf("session id before welcome = %q, want empty", s.SessionID())
+	}
+
+	s.Start(handler, onSession)
+
+	conn := srv.accept(t)
+	conn.welcome(t, "session-1", 10)
+
+	if got := receive(t, sessions); got != "session-1" {
+		t.Errorf("session id in onSession = %q, want %q", got, "session-1")
+	}
+	if got := s.SessionID(); got != "session-1" {
+		t.Errorf("SessionID() = %q, want %q", got, "session-1")
+	}
+}
+
+func TestEventSubWebsocketKeepaliveTimeout(t *testing.T) {
+	srv := newFakeEventSub(t)
+	s := newTestEventSubWebsocketService(t, srv.URL)
+	s.keepaliveGrace = 300 * time.Millisecond
+	sessions, onSession := sessionRecorder(s)
+	_, handler := notificationRecorder()
+
+	s.Start(handler, onSession)
+
+	conn := srv.accept(t)
+	conn.welcome(t, "session-1", 0)
+	receive(t, sessions)
+
+	// keepalive messages keep session open longer than the timeout
+	for i := range 5 {
+		time.Sleep(100 * time.Millisecond)
+		conn.send(t, eventSubMessageKeepalive, fmt.Sprintf("keepalive-%d", i), "", map[string]any{})
+	}
+	select {
+	case <-conn.closed:
+		t.Fatal("connection closed while keepalive messages were sent")
+	default:
+	}
+
+	conn.waitClosed(t)
+
+	conn = srv.accept(t)
+	conn.welcome(t, "session-2", 10)
+
+	if got := receive(t, sessions); got != "session-2" {
+		t.Errorf("session id after reconnect = %q, want %q", got, "session-2")
+	}
+}
+
+func TestEventSubWebsocketReconnect(t *testing.T) {
+	srv := newFakeEventSub(t)
+	s := newTestEventSubWebsocketService(t, srv.URL)
+	sessions, onSession := sessionRecorder(s)
+	notifications, handler := notificationRecorder()
+
+	s.Start(handler, onSession)
+
+	oldConn := srv.accept(t)
+	oldConn.welcome(t, "session-1", 10)
+	receive(t, sessions)
+
+	oldConn.send(t, eventSubMessageReconnect, "reconnect", "", map[string]any{
+		"session": map[string]any{
+			"id":            "session-1",
+			"status":        "reconnecting",
+			"reconnect_url": srv.URL + "/reconnect",
+		},
+	})
+	newConn := srv.accept(t)
+
+	// twitch keeps sending on the old connection until the new one is welcomed
+	oldConn.notification(t, eventSubMessageNotification, "msg-1", "channel.follow")
+	newConn.welcome(t, "session-1", 10)
+	oldConn.notification(t, eventSubMessageNotification, "msg-2", "channel.follow")
+	oldConn.Close(websocket.StatusNormalClosure, "")
+	newConn.notification(t, eventSubMessageNotification, "msg-3", "channel.follow")
+
+	for _, want := range []string{"msg-1", "msg-2", "msg-3"} {
+		got := receive(t, notifications)
+		if got.MessageID != want {
+			t.Errorf("notification = %q, want %q", got.MessageID, want)
+		}
+	}
+
+	select {
+	case got := <-sessions:
+		t.Errorf("onSession called after reconnect with %q, subscriptions are kept", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if got := s.SessionID(); got != "session-1" {
+		t.Errorf("SessionID() = %q, want %q", got, "session-1")
+	}
+}
+
+// fakeJetStream records published messages instead of sending them.
+type fakeJetStream struct {
+	jetstream.JetStream
+	published chan *nats.Msg
+}
+
+func (js *fakeJetStream) PublishMsg(ctx context.Context, msg *nats.Msg, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	js.published <- msg
+	return &jetstream.PubAck{}, nil
+}
+
+func TestEventSubWebsocketIngest(t *testing.T) {
+	srv := newFakeEventSub(t)
+	s := newTestEventSubWebsocketService(t, srv.URL)
+	_, onSession := sessionRecorder(s)
+	js := &fakeJetStream{published: make(chan *nats.Msg, 4)}
+	ingestService := &IngestService{
+		js:     js,
+		logger: applog.NewServiceLogger("ingest-service"),
+	}
+
+	s.Start(ingestService.Enqueue, onSession)
+
+	conn := srv.accept(t)
+	conn.welcome(t, "session-1", 10)
+	conn.notification(t, eventSubMessageNotification, "msg-1", "channel.chat.message")
+	conn.notification(t, eventSubMessageRevocation, "msg-2", "channel.follow")
+
+	tests := []struct {
+		messageID   string
+		messageType string
+		subType     string
+	}{
+		{"msg-1", eventSubMessageNotification, "channel.chat.message"},
+		{"msg-2", eventSubMessageRevocation, "channel.follow"},
+	}
+	for _, tt := range tests {
+		msg := receive(t, js.published)
+
+		if msg.Subject != IngestSubject {
+			t.Errorf("subject = %q, want %q", msg.Subject, IngestSubject)
+		}
+		if got := msg.Header.Get(jetstream.MsgIDHeader); got != tt.messageID {
+			t.Errorf("dedup id = %q, want %q", got, tt.messageID)
+		}
+		if got := msg.Header.Get(HeaderMessageID); got != tt.messageID {
+			t.Errorf("message id = %q, want %q", got, tt.messageID)
+		}
+		if got := msg.Header.Get(HeaderMessageType); got != tt.messageType {
+			t.Errorf("message type = %q, want %q", got, tt.messageType)
+		}
+		if got := msg.Header.Get(HeaderSubscriptionType); got != tt.subType {
+			t.Errorf("subscription type = %q, want %q", got, tt.subType)
+		}
+
+		var body struct {
+			Subscription struct {
+				Type string `json:"type"`
+			} `json:"subscription"`
+		}
+		err := json.Unmarshal(msg.Data, &body)
+		if err != nil {
+			t.Fatalf("body is not webhook payload: %v", err)
+		}
+		if body.Subscription.Type != tt.subType {
+			t.Errorf("body subscription type = %q, want %q", body.Subscription.Type, tt.subType)
+		}
+	}
+}
@@ -3,7 +3,11 @@ package service
 import (
 	"bytes"
 	"context"
-	
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
@@ -17,6 +21,8 @@ import (
 	"github.com/nicklaw5/helix/v2"
 )
 
+const helixRequestTimeout = 10 * time.Second
+
 // TODO: right now there is no cleanup for clients
 type HelixManager struct {
 	logger       applog.Logger
@@ -26,7 +32,13 @@ type HelixManager struct {
 	appClient *helix.Client
 
 	clients map[string]*helix.Client
-	mu      sync.RWMutex
+	// refreshed persists tokens of the client, the same way helix client does
+	// on its own refresh
+	refreshed map[*helix.Client]func(accessToken, refreshToken string)
+	mu        sync.RWMutex
+
+	// httpClient is used for raw requests, see Request
+	httpClient *http.Client
 
 	authModule *sharedService.AuthModule
 	cache      jetstream.KeyValue
@@ -61,7 +73,10 @@ func NewHelixManager(
 		clientSecret: clientSecret,
 		appClient:    appClient,
 		clients:      make(map[string]*helix.Client),
+		refreshed:    make(map[*helix.Client]func(accessToken, refreshToken string)),
+		httpClient:   &http.Client{Timeout: helixRequestTimeout},
 		authModule:   authModule,
+		cache:        cache,
 	}
 }
 
@@ -104,7 +119,7 @@ func (hm *HelixManager) GetByProvider(ctx context.Context, provider data.AuthPro
 		RefreshToken:    provider.RefreshToken,
 	})
 
-	client.OnUserAccessTokenRefreshed(func(newAccessToken, newRefreshToken string) {
+	refreshed := func(newAccessToken, newRefreshToken string) {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
     ctx = trace.Context(ctx, trace.New())
@@ -123,9 +138,136 @@ func (hm *HelixManager) GetByProvider(ctx context.Context, provider data.AuthPro
 		if err != nil {
 			hm.logger.ErrorContext(ctx, "failed to update tokens", "providerID", provider.ID, "providerUserID", provider.ProviderUserID)
 		}
-	})
+	}
+	client.OnUserAccessTokenRefreshed(refreshed)
 
 	hm.clients[provider.ProviderUserID] = client
+	hm.refreshed[client] = refreshed
 
 	return client
 }
+
+// Request sends request to helix endpoint that helix client doesn't support
+// (yet), access token is taken from the client. User access token is
+// refreshed and request is retried once on 401, like helix client does.
+func (hm *HelixManager) Request(
+	ctx context.Context,
+	client *helix.Client,
+	method string,
+	path string,
+	query url.Values,
+	body any,
+	respData any,
+) (helix.ResponseCommon, error) {
+	var res helix.ResponseCommon
+
+	var b []byte
+	if body != nil {
+		var err error
+		b, err = json.Marshal(body)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	res, err := hm.request(ctx, client, method, path, query, b, respData)
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	if client.GetUserAccessToken() == "" || client.GetRefreshToken() == "" {
+		return res, nil
+	}
+	err = hm.refreshUserToken(ctx, client)
+	if err != nil {
+		hm.logger.ErrorContext(ctx, "cannot refresh user access token", "err", err)
+		return res, nil
+	}
+
+	return hm.request(ctx, client, method, path, query, b, respData)
+}
+
+func (hm *HelixManager) request(
+	ctx context.Context,
+	client *helix.Client,
+	method string,
+	path string,
+	query url.Values,
+	body []byte,
+	respData any,
+) (helix.ResponseCommon, error) {
+	var res helix.ResponseCommon
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, helix.DefaultAPIBaseURL+path, reqBody)
+	if err != nil {
+		return res, err
+	}
+	req.URL.RawQuery = query.Encode()
+
+	token := client.GetUserAccessToken()
+	if token == "" {
+		token = client.GetAppAccessToken()
+	}
+	req.Header.Set("Client-ID", hm.clientID)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := hm.httpClient.Do(req)
+	if err != nil {
+		return res, err
+	}
+	defer resp.Body.Close()
+
+	res.StatusCode = resp.StatusCode
+	res.Header = resp.Header
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return res, err
+	}
+	if len(b) == 0 {
+		return res, nil
+	}
+
+	if resp.StatusCode >= 400 {
+		json.Unmarshal(b, &res)
+		return res, nil
+	}
+
+	if respData != nil {
+		err = json.Unmarshal(b, respData)
+	}
+
+	return res, err
+}
+
+// refreshUserToken refreshes user access token of the client and persists
+// new tokens.
+func (hm *HelixManager) refreshUserToken(ctx context.Context, client *helix.Client) error {
+	res, err := client.RefreshUserAccessToken(client.GetRefreshToken())
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to refresh token: (%d: %s)", res.StatusCode, res.ErrorMessage)
+	}
+
+	client.SetUserAccessToken(res.Data.AccessToken)
+	client.SetRefreshToken(res.Data.RefreshToken)
+
+	hm.mu.RLock()
+	refreshed, ok := hm.refreshed[client]
+	hm.mu.RUnlock()
+	if ok {
+		refreshed(res.Data.AccessToken, res.Data.RefreshToken)
+	}
+
+	return nil
+}
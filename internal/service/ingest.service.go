@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/arnokay/arnobot-shared/apperror"
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/arnokay/arnobot-shared/pkg/assert"
+	"github.com/arnokay/arnobot-shared/trace"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nicklaw5/helix/v2"
+
+	"github.com/arnokay/arnobot-twitch/internal/config"
+	"github.com/arnokay/arnobot-twitch/internal/data"
+)
+
+const (
+	ingestHandlerTimeout = 20 * time.Second
+	// ingestAckWait has to be longer than handler timeout, message waiting for
+	// a busy worker is kept in progress meanwhile
+	ingestAckWait = time.Minute
+)
+
+const (
+	IngestStream   = "TWITCH_EVENTSUB"
+	IngestSubject  = "twitch.eventsub.ingest"
+	IngestConsumer = "twitch-eventsub-worker"
+
+	HeaderMessageID        = "Twitch-Eventsub-Message-Id"
+	HeaderMessageType      = "Twitch-Eventsub-Message-Type"
+	HeaderSubscriptionType = "Twitch-Eventsub-Subscription-Type"
+	HeaderTraceID          = "Trace-Id"
+)
+
+// IngestHandler enriches and forwards notification, returned error means
+// notification will be retried.
+type IngestHandler func(ctx context.Context, notification data.EventSubNotification) error
+
+// IngestService persists eventsub notifications to jetstream, so webhook can
+// be acknowledged right away, and handles them with a pool of workers.
+// Notifications of one broadcaster are always handled by the same worker, so
+// they are forwarded in order, unless one of them is retried.
+// Notifications that still fail after all retries go to dead letter stream.
+type IngestService struct {
+	js       jetstream.JetStream
+	consumer jetstream.Consumer
+
+	deadLetterService *DeadLetterService
+
+	workers        int
+	maxDeliver     int
+	backoff        []time.Duration
+	handlerTimeout time.Duration
+
+	consumeCtx jetstream.ConsumeContext
+	// jobs has a channel per worker
+	jobs []chan jetstream.Msg
+	quit chan struct{}
+	wg   sync.WaitGroup
+
+	logger applog.Logger
+}
+
+func NewIngestService(
+	ctx context.Context,
+	js jetstream.JetStream,
+	deadLetterService *DeadLetterService,
+) *IngestService {
+	logger := applog.NewServiceLogger("ingest-service")
+
+	backoff, err := config.ParseBackoff(config.Config.Ingest.Backoff)
+	assert.NoError(err, "ingest: cannot parse backoff")
+	_, err = js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      IngestStream,
+		Subjects:  []string{IngestSubject},
+		Retention: jetstream.WorkQueuePolicy,
+		// twitch message id is used as nats message id
+		Duplicates: 10 * time.Minute,
+	})
+	assert.NoError(err, "ingest: cannot create stream")
+
+	// retries are done with nak, so consumer redelivers forever and service
+	// decides when to give up
+	consumer, err := js.CreateOrUpdateConsumer(ctx, IngestStream, jetstream.ConsumerConfig{
+		Durable:    IngestConsumer,
+		AckPolicy:  jetstream.AckExplicitPolicy,
+		AckWait:    ingestAckWait,
+		MaxDeliver: -1,
+	})
+	assert.NoError(err, "ingest: cannot create consumer")
+
+	return &IngestService{
+		js:                js,
+		consumer:          consumer,
+		deadLetterService: deadLetterService,
+		workers:           config.Config.Ingest.Workers,
+		maxDeliver:        config.Config.Ingest.MaxDeliver,
+		backoff:           backoff,
+		handlerTimeout:    ingestHandlerTimeout,
+		logger:            logger,
+	}
+}
+
+func (s *IngestService) Enqueue(ctx context.Context, notification data.EventSubNotification) error {
+	msg := nats.NewMsg(IngestSubject)
+	msg.Data = notification.Body
+	msg.Header.Set(jetstream.MsgIDHeader, notification.MessageID)
+	msg.Header.Set(HeaderMessageID, notification.MessageID)
+	msg.Header.Set(HeaderMessageType, notification.MessageType)
+	msg.Header.Set(HeaderSubscriptionType, notification.SubscriptionType)
+	msg.Header.Set(HeaderTraceID, trace.FromContext(ctx))
+
+	_, err := s.js.PublishMsg(ctx, msg)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot enqueue notification", "err", err, "messageID", notification.MessageID)
+		return apperror.ErrInternal
+	}
+
+	return nil
+}
+
+// Start runs workers until Stop is called.
+func (s *IngestService) Start(handler IngestHandler) error {
+	s.jobs = make([]chan jetstream.Msg, s.workers)
+	s.quit = make(chan struct{})
+
+	for i := range s.workers {
+		s.jobs[i] = make(chan jetstream.Msg)
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for {
+				select {
+				case msg := <-s.jobs[i]:
+					s.process(msg, handler)
+				case <-s.quit:
+					return
+				}
+			}
+		}()
+	}
+
+	consumeCtx, err := s.consumer.Consume(
+		s.dispatch,
+		// messages are handed to workers one by one, the one that is pulled
+		// ahead only waits until the current one is handed
+		jetstream.PullMaxMessages(1),
+	)
+	if err != nil {
+		close(s.quit)
+		s.wg.Wait()
+		return err
+	}
+	s.consumeCtx = consumeCtx
+
+	s.logger.Info("ingest workers started", "workers", s.workers)
+
+	return nil
+}
+
+// Stop waits until notifications that are already received are handled.
+func (s *IngestService) Stop(ctx context.Context) {
+	if s.consumeCtx == nil {
+		return
+	}
+
+	s.consumeCtx.Drain()
+	select {
+	case <-s.consumeCtx.Closed():
+	case <-ctx.Done():
+		s.consumeCtx.Stop()
+	}
+
+	close(s.quit)
+	s.wg.Wait()
+}
+
+// dispatch hands message to the worker of its broadcaster, message is kept in
+// progress while the worker is busy.
+func (s *IngestService) dispatch(msg jetstream.Msg) {
+	jobs := s.jobs[s.partition(msg)]
+
+	ticker := time.NewTicker(ingestAckWait / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case jobs <- msg:
+			return
+		case <-ticker.C:
+			msg.InProgress()
+		case <-s.quit:
+			// not acked message is redelivered after restart
+			return
+		}
+	}
+}
+
+// partition returns worker of the notification broadcaster.
+func (s *IngestService) partition(msg jetstream.Msg) int {
+	var body struct {
+		Subscription helix.EventSubSubscription `json:"subscription"`
+	}
+	// message that cannot be parsed fails in handler anyway
+	json.Unmarshal(msg.Data(), &body)
+
+	h := fnv.New32a()
+	h.Write([]byte(data.GetConditionBroadcasterID(body.Subscription.Condition)))
+
+	return int(h.Sum32() % uint32(len(s.jobs)))
+}
+
+func (s *IngestService) process(msg jetstream.Msg, handler IngestHandler) {
+	// ack wait starts over, so handler has the whole timeout
+	msg.InProgress()
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.handlerTimeout)
+	defer cancel()
+	traceID := msg.Headers().Get(HeaderTraceID)
+	if traceID == "" {
+		traceID = trace.New()
+	}
+	ctx = trace.Context(ctx, traceID)
+
+	var delivered uint64 = 1
+	if md, err := msg.Metadata(); err == nil {
+		delivered = md.NumDelivered
+	}
+
+	err := handler(ctx, data.EventSubNotification{
+		MessageID:        msg.Headers().Get(HeaderMessageID),
+		MessageType:      msg.Headers().Get(HeaderMessageType),
+		SubscriptionType: msg.Headers().Get(HeaderSubscriptionType),
+		Body:             msg.Data(),
+	})
+	if err == nil {
+		msg.Ack()
+		return
+	}
+
+	if IsPermanentErr(err) {
+		s.logger.ErrorContext(ctx, "notification dropped, retry will not fix it",
+			"err", err,
+			"messageID", msg.Headers().Get(HeaderMessageID),
+			"subType", msg.Headers().Get(HeaderSubscriptionType),
+		)
+		msg.Ack()
+		return
+	}
+
+	if delivered < uint64(s.maxDeliver) {
+		delay := s.backoff[min(int(delivered), len(s.backoff))-1]
+		s.logger.WarnContext(ctx, "notification failed, will retry", "err", err, "delivered", delivered, "delay", delay)
+		msg.NakWithDelay(delay)
+		return
+	}
+
+	dlqErr := s.deadLetterService.Create(ctx, data.DeadLetterCreate{
+		Kind:     data.DeadLetterKindEventSub,
+		Subject:  IngestSubject,
+		Header:   msg.Headers(),
+		Body:     msg.Data(),
+		Err:      err,
+		Attempts: int(delivered),
+	})
+	if dlqErr != nil {
+		// keep it in the ingest stream rather than lose it
+		msg.NakWithDelay(s.backoff[len(s.backoff)-1])
+		return
+	}
+	msg.Term()
+}
+
+// IsPermanentErr reports whether handler failed on notification itself, e.g.
+// channel has no selected bot or event cannot be parsed.
+func IsPermanentErr(err error) bool {
+	return hasErrCode(err, apperror.CodeNotFound) || hasErrCode(err, apperror.CodeInvalidInput)
+}
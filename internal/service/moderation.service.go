@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/arnokay/arnobot-shared/storage"
+
+	"github.com/arnokay/arnobot-twitch/internal/dbtransform"
+	"github.com/arnokay/arnobot-twitch/internal/events"
+	"github.com/arnokay/arnobot-twitch/internal/twitchdb"
+)
+
+type ModerationService struct {
+	storage storage.Storager
+
+	logger applog.Logger
+}
+
+func NewModerationService(
+	store storage.Storager,
+) *ModerationService {
+	logger := applog.NewServiceLogger("moderation-service")
+
+	return &ModerationService{
+		storage: store,
+		logger:  logger,
+	}
+}
+
+// ActionCreate saves moderation action once per eventsub message, redelivered
+// notification doesn't create another row.
+func (s *ModerationService) ActionCreate(ctx context.Context, eventSubMessageID string, arg events.ModerationAction) error {
+	// table is owned by this module, so it is not in the shared queries
+	created, err := twitchdb.New(s.storage.Database(ctx)).TwitchModerationActionCreate(
+		ctx,
+		dbtransform.NewModerationActionCreateToDB(eventSubMessageID, arg),
+	)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot save moderation action", "err", err, "action", arg.Action, "broadcasterID", arg.BroadcasterID)
+		return s.storage.HandleErr(ctx, err)
+	}
+	if created == 0 {
+		s.logger.DebugContext(ctx, "moderation action is already saved", "eventSubMessageID", eventSubMessageID)
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+
+	"github.com/arnokay/arnobot-shared/applog"
+	sharedEvents "github.com/arnokay/arnobot-shared/events"
+	sharedService "github.com/arnokay/arnobot-shared/service"
+	sharedTopics "github.com/arnokay/arnobot-shared/topics"
+	"github.com/nats-io/nats.go"
+
+	"github.com/arnokay/arnobot-twitch/internal/events"
+	"github.com/arnokay/arnobot-twitch/internal/topics"
+)
+
+// PlatformModuleOut extends the shared platform module with twitch specific
+// events.
+type PlatformModuleOut struct {
+	*sharedService.PlatformModuleOut
+
+	mb     *nats.Conn
+	logger applog.Logger
+}
+
+func NewPlatformModuleOut(mb *nats.Conn) *PlatformModuleOut {
+	logger := applog.NewServiceLogger("twitch-platform-module-out")
+
+	return &PlatformModuleOut{
+		PlatformModuleOut: sharedService.NewPlatformModuleOut(mb),
+		mb:                mb,
+		logger:            logger,
+	}
+}
+
+// ChatMessageNotify overrides the shared one to publish message with
+// fragments.
+func (s *PlatformModuleOut) ChatMessageNotify(ctx context.Context, arg events.Message) error {
+	return notify(ctx, s, sharedTopics.PlatformBroadcasterChatMessageNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) SubscriptionRevokedNotify(ctx context.Context, arg events.SubscriptionRevoked) error {
+	return notify(ctx, s, topics.PlatformBroadcasterSubscriptionRevokedNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) StreamOnlineNotify(ctx context.Context, arg events.StreamOnline) error {
+	return notify(ctx, s, topics.PlatformBroadcasterStreamOnlineNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) StreamOfflineNotify(ctx context.Context, arg events.StreamOffline) error {
+	return notify(ctx, s, topics.PlatformBroadcasterStreamOfflineNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) FollowNotify(ctx context.Context, arg events.Follow) error {
+	return notify(ctx, s, topics.PlatformBroadcasterFollowNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) SubscribeNotify(ctx context.Context, arg events.Subscribe) error {
+	return notify(ctx, s, topics.PlatformBroadcasterSubscribeNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) SubscriptionMessageNotify(ctx context.Context, arg events.SubscriptionMessage) error {
+	return notify(ctx, s, topics.PlatformBroadcasterSubscriptionMessageNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) SubscriptionGiftNotify(ctx context.Context, arg events.SubscriptionGift) error {
+	return notify(ctx, s, topics.PlatformBroadcasterSubscriptionGiftNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) SubscriptionEndNotify(ctx context.Context, arg events.SubscriptionEnd) error {
+	return notify(ctx, s, topics.PlatformBroadcasterSubscriptionEndNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) CheerNotify(ctx context.Context, arg events.Cheer) error {
+	return notify(ctx, s, topics.PlatformBroadcasterCheerNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) RaidNotify(ctx context.Context, arg events.Raid) error {
+	return notify(ctx, s, topics.PlatformBroadcasterRaidNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) RedemptionAddNotify(ctx context.Context, arg events.RewardRedemption) error {
+	return notify(ctx, s, topics.PlatformBroadcasterRedemptionAddNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) RedemptionUpdateNotify(ctx context.Context, arg events.RewardRedemption) error {
+	return notify(ctx, s, topics.PlatformBroadcasterRedemptionUpdateNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) PollBeginNotify(ctx context.Context, arg events.Poll) error {
+	return notify(ctx, s, topics.PlatformBroadcasterPollBeginNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) PollProgressNotify(ctx context.Context, arg events.Poll) error {
+	return notify(ctx, s, topics.PlatformBroadcasterPollProgressNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) PollEndNotify(ctx context.Context, arg events.Poll) error {
+	return notify(ctx, s, topics.PlatformBroadcasterPollEndNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) PredictionBeginNotify(ctx context.Context, arg events.Prediction) error {
+	return notify(ctx, s, topics.PlatformBroadcasterPredictionBeginNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) PredictionProgressNotify(ctx context.Context, arg events.Prediction) error {
+	return notify(ctx, s, topics.PlatformBroadcasterPredictionProgressNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) PredictionLockNotify(ctx context.Context, arg events.Prediction) error {
+	return notify(ctx, s, topics.PlatformBroadcasterPredictionLockNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) PredictionEndNotify(ctx context.Context, arg events.Prediction) error {
+	return notify(ctx, s, topics.PlatformBroadcasterPredictionEndNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) HypeTrainBeginNotify(ctx context.Context, arg events.HypeTrain) error {
+	return notify(ctx, s, topics.PlatformBroadcasterHypeTrainBeginNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) HypeTrainProgressNotify(ctx context.Context, arg events.HypeTrain) error {
+	return notify(ctx, s, topics.PlatformBroadcasterHypeTrainProgressNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) HypeTrainEndNotify(ctx context.Context, arg events.HypeTrain) error {
+	return notify(ctx, s, topics.PlatformBroadcasterHypeTrainEndNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) ChatNotificationNotify(ctx context.Context, arg events.ChatNotification) error {
+	return notify(ctx, s, topics.PlatformBroadcasterChatNotificationNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) ModerationActionNotify(ctx context.Context, arg events.ModerationAction) error {
+	return notify(ctx, s, topics.PlatformBroadcasterModerationActionNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) MessageDeleteNotify(ctx context.Context, arg events.MessageDelete) error {
+	return notify(ctx, s, topics.PlatformBroadcasterMessageDeleteNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) ChatClearNotify(ctx context.Context, arg events.ChatClear) error {
+	return notify(ctx, s, topics.PlatformBroadcasterChatClearNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) ChatClearUserMessagesNotify(ctx context.Context, arg events.ChatClearUserMessages) error {
+	return notify(ctx, s, topics.PlatformBroadcasterChatClearUserNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) ChannelUpdateNotify(ctx context.Context, arg events.ChannelUpdate) error {
+	return notify(ctx, s, topics.PlatformBroadcasterChannelUpdateNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) AdBreakBeginNotify(ctx context.Context, arg events.AdBreakBegin) error {
+	return notify(ctx, s, topics.PlatformBroadcasterAdBreakBeginNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) AutomodMessageHoldNotify(ctx context.Context, arg events.AutomodMessage) error {
+	return notify(ctx, s, topics.PlatformBroadcasterAutomodHoldNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) AutomodMessageUpdateNotify(ctx context.Context, arg events.AutomodMessage) error {
+	return notify(ctx, s, topics.PlatformBroadcasterAutomodUpdateNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) SharedChatBeginNotify(ctx context.Context, arg events.SharedChatSession) error {
+	return notify(ctx, s, topics.PlatformBroadcasterSharedChatBeginNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) SharedChatUpdateNotify(ctx context.Context, arg events.SharedChatSession) error {
+	return notify(ctx, s, topics.PlatformBroadcasterSharedChatUpdateNotify, arg.EventCommon, arg)
+}
+
+func (s *PlatformModuleOut) SharedChatEndNotify(ctx context.Context, arg events.SharedChatSession) error {
+	return notify(ctx, s, topics.PlatformBroadcasterSharedChatEndNotify, arg.EventCommon, arg)
+}
+
+func notify[T any](
+	ctx context.Context,
+	s *PlatformModuleOut,
+	topic string,
+	common sharedEvents.EventCommon,
+	arg T,
+) error {
+	topicBuilder := sharedTopics.TopicBuilder(topic)
+	topicBuilder.Platform(common.Platform)
+	topicBuilder.BroadcasterID(common.BroadcasterID)
+
+	return sharedService.HandlePublish(ctx, s.mb, s.logger, topicBuilder.Build(), arg)
+}
@@ -6,10 +6,15 @@ import (
 
 type Services struct {
 	AuthModule         *service.AuthModule
-	PlatformModule     *service.PlatformModuleOut
+	PlatformModule     *PlatformModuleOut
 	HelixManager       *HelixManager
 	BotService         *BotService
 	WebhookService     *WebhookService
 	TwitchService      *TwitchService
+	ModerationService  *ModerationService
+	SharedChatService  *SharedChatService
+	IngestService      *IngestService
+	DeadLetterService  *DeadLetterService
+	EventSubWebsocket  *EventSubWebsocketService
 	TransactionService service.ITransactionService
 }
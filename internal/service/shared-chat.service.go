@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/arnokay/arnobot-shared/apperror"
+	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/arnokay/arnobot-twitch/internal/data"
+)
+
+// SharedChatService tracks shared chat session of every broadcaster, twitch
+// sends begin/update/end to each participant separately.
+type SharedChatService struct {
+	cache jetstream.KeyValue
+
+	logger applog.Logger
+}
+
+func NewSharedChatService(
+	cache jetstream.KeyValue,
+) *SharedChatService {
+	logger := applog.NewServiceLogger("shared-chat-service")
+
+	return &SharedChatService{
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// sharedChatSessionTTL drops session when end event is missed, session is
+// refreshed on every update.
+const sharedChatSessionTTL = 12 * time.Hour
+
+// sharedChatSetAttempts is how many times session is recreated when another
+// update creates it in between.
+const sharedChatSetAttempts = 3
+
+func sharedChatKey(broadcasterID string) string {
+	return "sharedchat.session." + broadcasterID
+}
+
+// SessionSet saves or replaces session of the broadcaster.
+func (s *SharedChatService) SessionSet(ctx context.Context, broadcasterID string, session data.SharedChatSession) error {
+	b, err := json.Marshal(session)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot marshal shared chat session", "err", err)
+		return apperror.ErrInternal
+	}
+
+	// ttl can be set only on create, so session is created again to refresh it
+	key := sharedChatKey(broadcasterID)
+	for range sharedChatSetAttempts {
+		err = s.cache.Delete(ctx, key)
+		if err != nil && !errors.Is(err, jetstream.ErrKeyNotFound) {
+			s.logger.ErrorContext(ctx, "cannot save shared chat session", "err", err, "broadcasterID", broadcasterID)
+			return apperror.ErrInternal
+		}
+		_, err = s.cache.Create(ctx, key, b, jetstream.KeyTTL(sharedChatSessionTTL))
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, jetstream.ErrKeyExists) {
+			s.logger.ErrorContext(ctx, "cannot save shared chat session", "err", err, "broadcasterID", broadcasterID)
+			return apperror.ErrInternal
+		}
+		// another update created session after the delete
+		s.logger.DebugContext(ctx, "shared chat session is created concurrently, retrying", "broadcasterID", broadcasterID)
+	}
+
+	s.logger.ErrorContext(ctx, "cannot save shared chat session", "err", err, "broadcasterID", broadcasterID)
+	return apperror.ErrInternal
+}
+
+// SessionGet returns apperror.ErrNotFound when broadcaster is not in shared
+// chat.
+func (s *SharedChatService) SessionGet(ctx context.Context, broadcasterID string) (data.SharedChatSession, error) {
+	entry, err := s.cache.Get(ctx, sharedChatKey(broadcasterID))
+	if err != nil {
+		if errors.Is(err, jetstream.ErrKeyNotFound) {
+			return data.SharedChatSession{}, apperror.ErrNotFound
+		}
+		s.logger.ErrorContext(ctx, "cannot get shared chat session", "err", err, "broadcasterID", broadcasterID)
+		return data.SharedChatSession{}, apperror.ErrInternal
+	}
+
+	var session data.SharedChatSession
+	err = json.Unmarshal(entry.Value(), &session)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot unmarshal shared chat session", "err", err, "broadcasterID", broadcasterID)
+		return data.SharedChatSession{}, apperror.ErrInternal
+	}
+
+	return session, nil
+}
+
+func (s *SharedChatService) SessionDelete(ctx context.Context, broadcasterID string) error {
+	err := s.cache.Purge(ctx, sharedChatKey(broadcasterID))
+	if err != nil && !errors.Is(err, jetstream.ErrKeyNotFound) {
+		s.logger.ErrorContext(ctx, "cannot delete shared chat session", "err", err, "broadcasterID", broadcasterID)
+		return apperror.ErrInternal
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/arnokay/arnobot-shared/apperror"
+	"github.com/nicklaw5/helix/v2"
+
+	"github.com/arnokay/arnobot-twitch/internal/data"
+)
+
+func (s *TwitchService) AdStart(ctx context.Context, arg data.AdStart) (data.AdStartResult, error) {
+	client, err := s.userClient(ctx, arg.BroadcasterID)
+	if err != nil {
+		return data.AdStartResult{}, err
+	}
+
+	res, err := client.StartCommercial(&helix.StartCommercialParams{
+		BroadcasterID: arg.BroadcasterID,
+		Length:        helix.AdLengthEnum(arg.Length),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot start commercial", "err", err, "arg", arg)
+		return data.AdStartResult{}, apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot start commercial", "status", res.StatusCode, "err_msg", res.ErrorMessage, "arg", arg)
+		return data.AdStartResult{}, responseErr(res.ResponseCommon)
+	}
+	if len(res.Data.AdDetails) == 0 {
+		return data.AdStartResult{}, apperror.ErrExternal
+	}
+
+	details := res.Data.AdDetails[0]
+
+	return data.AdStartResult{
+		Length:     int(details.Length),
+		Message:    details.Message,
+		RetryAfter: details.RetryAfter,
+	}, nil
+}
+
+func (s *TwitchService) AdScheduleGet(ctx context.Context, arg data.AdScheduleGet) (data.AdSchedule, error) {
+	return s.adSchedule(ctx, http.MethodGet, "/channels/ads", arg.BroadcasterID)
+}
+
+func (s *TwitchService) AdSnooze(ctx context.Context, arg data.AdSnooze) (data.AdSchedule, error) {
+	return s.adSchedule(ctx, http.MethodPost, "/channels/ads/schedule/snooze", arg.BroadcasterID)
+}
+
+// adSchedule requests endpoints that return ad schedule, helix client has no
+// support for them.
+func (s *TwitchService) adSchedule(ctx context.Context, method, path, broadcasterID string) (data.AdSchedule, error) {
+	client, err := s.userClient(ctx, broadcasterID)
+	if err != nil {
+		return data.AdSchedule{}, err
+	}
+
+	var schedule struct {
+		Data []data.HelixAdSchedule `json:"data"`
+	}
+	res, err := s.helixManager.Request(
+		ctx,
+		client,
+		method,
+		path,
+		url.Values{"broadcaster_id": {broadcasterID}},
+		nil,
+		&schedule,
+	)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot request ad schedule", "err", err, "path", path, "broadcasterID", broadcasterID)
+		return data.AdSchedule{}, apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot request ad schedule", "status", res.StatusCode, "err_msg", res.ErrorMessage, "path", path, "broadcasterID", broadcasterID)
+		return data.AdSchedule{}, responseErr(res)
+	}
+	if len(schedule.Data) == 0 {
+		return data.AdSchedule{}, apperror.ErrExternal
+	}
+
+	return data.NewAdScheduleFromHelix(schedule.Data[0]), nil
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/arnokay/arnobot-shared/apperror"
+)
+
+// BotModerateHeldMessage allows or denies message held by automod, bot has to
+// be a moderator of the channel.
+// helix client sends params of this endpoint with wrong json keys, so it is
+// requested directly.
+func (s *TwitchService) BotModerateHeldMessage(
+	ctx context.Context,
+	botID string,
+	messageID string,
+	allow bool,
+) error {
+	client, err := s.userClient(ctx, botID)
+	if err != nil {
+		return err
+	}
+
+	action := "DENY"
+	if allow {
+		action = "ALLOW"
+	}
+
+	res, err := s.helixManager.Request(
+		ctx,
+		client,
+		http.MethodPost,
+		"/moderation/automod/message",
+		url.Values{},
+		map[string]string{
+			"user_id": botID,
+			"msg_id":  messageID,
+			"action":  action,
+		},
+		nil,
+	)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot moderate held message", "err", err, "botID", botID, "messageID", messageID, "action", action)
+		return apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot moderate held message", "status", res.StatusCode, "err_msg", res.ErrorMessage, "botID", botID, "messageID", messageID, "action", action)
+		return responseErr(res)
+	}
+
+	return nil
+}
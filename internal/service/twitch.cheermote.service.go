@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/arnokay/arnobot-shared/apperror"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/nicklaw5/helix/v2"
+)
+
+const cheermotePrefixesTTL = time.Hour
+
+func cheermotePrefixesKey(broadcasterID string) string {
+	return "cheermotes." + broadcasterID
+}
+
+// AppCheermotePrefixes returns lowercase prefixes of global and channel
+// cheermotes that can be used in the channel, they are cached because every
+// cheer needs them.
+func (s *TwitchService) AppCheermotePrefixes(ctx context.Context, broadcasterID string) ([]string, error) {
+	entry, err := s.cache.Get(ctx, cheermotePrefixesKey(broadcasterID))
+	if err == nil {
+		var prefixes []string
+		err = json.Unmarshal(entry.Value(), &prefixes)
+		if err == nil {
+			return prefixes, nil
+		}
+	}
+	if err != nil && !errors.Is(err, jetstream.ErrKeyNotFound) {
+		s.logger.WarnContext(ctx, "cannot get cached cheermotes", "err", err, "broadcasterID", broadcasterID)
+	}
+
+	client := s.helixManager.GetApp(ctx)
+
+	res, err := client.GetCheermotes(&helix.CheermotesParams{
+		BroadcasterID: broadcasterID,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot get cheermotes", "err", err, "broadcasterID", broadcasterID)
+		return nil, apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot get cheermotes", "status", res.StatusCode, "err_msg", res.ErrorMessage, "broadcasterID", broadcasterID)
+		return nil, responseErr(res.ResponseCommon)
+	}
+	if len(res.Data.Cheermotes) == 0 {
+		return nil, apperror.ErrExternal
+	}
+
+	prefixes := make([]string, 0, len(res.Data.Cheermotes))
+	for _, cheermote := range res.Data.Cheermotes {
+		prefixes = append(prefixes, strings.ToLower(cheermote.Prefix))
+	}
+
+	b, _ := json.Marshal(prefixes)
+	_, err = s.cache.Create(ctx, cheermotePrefixesKey(broadcasterID), b, jetstream.KeyTTL(cheermotePrefixesTTL))
+	if err != nil && !errors.Is(err, jetstream.ErrKeyExists) {
+		s.logger.WarnContext(ctx, "cannot cache cheermotes", "err", err, "broadcasterID", broadcasterID)
+	}
+
+	return prefixes, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+
+	"github.com/arnokay/arnobot-shared/apperror"
+	"github.com/nicklaw5/helix/v2"
+
+	"github.com/arnokay/arnobot-twitch/internal/data"
+)
+
+func (s *TwitchService) PollCreate(ctx context.Context, arg data.PollCreate) (data.Poll, error) {
+	client, err := s.userClient(ctx, arg.BroadcasterID)
+	if err != nil {
+		return data.Poll{}, err
+	}
+
+	choices := make([]helix.PollChoiceParam, 0, len(arg.Choices))
+	for _, choice := range arg.Choices {
+		choices = append(choices, helix.PollChoiceParam{Title: choice})
+	}
+
+	res, err := client.CreatePoll(&helix.CreatePollParams{
+		BroadcasterID:              arg.BroadcasterID,
+		Title:                      arg.Title,
+		Choices:                    choices,
+		Duration:                   arg.Duration,
+		ChannelPointsVotingEnabled: arg.ChannelPointsPerVote > 0,
+		ChannelPointsPerVote:       arg.ChannelPointsPerVote,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot create poll", "err", err, "arg", arg)
+		return data.Poll{}, apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot create poll", "status", res.StatusCode, "err_msg", res.ErrorMessage, "arg", arg)
+		return data.Poll{}, responseErr(res.ResponseCommon)
+	}
+	if len(res.Data.Polls) == 0 {
+		return data.Poll{}, apperror.ErrExternal
+	}
+
+	return newPollFromHelix(res.Data.Polls[0]), nil
+}
+
+func (s *TwitchService) PollEnd(ctx context.Context, arg data.PollEnd) (data.Poll, error) {
+	client, err := s.userClient(ctx, arg.BroadcasterID)
+	if err != nil {
+		return data.Poll{}, err
+	}
+
+	status := "TERMINATED"
+	if arg.Archive {
+		status = "ARCHIVED"
+	}
+
+	res, err := client.EndPoll(&helix.EndPollParams{
+		BroadcasterID: arg.BroadcasterID,
+		ID:            arg.ID,
+		Status:        status,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot end poll", "err", err, "arg", arg)
+		return data.Poll{}, apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot end poll", "status", res.StatusCode, "err_msg", res.ErrorMessage, "arg", arg)
+		return data.Poll{}, responseErr(res.ResponseCommon)
+	}
+	if len(res.Data.Polls) == 0 {
+		return data.Poll{}, apperror.ErrExternal
+	}
+
+	return newPollFromHelix(res.Data.Polls[0]), nil
+}
+
+func newPollFromHelix(poll helix.Poll) data.Poll {
+	result := data.Poll{
+		ID:            poll.ID,
+		BroadcasterID: poll.BroadcasterID,
+		Title:         poll.Title,
+		Choices:       data.NewPollChoicesFromHelix(poll.Choices),
+		Status:        poll.Status,
+		Duration:      poll.Duration,
+		StartedAt:     poll.StartedAt.Time,
+	}
+	if poll.ChannelPointsVotingEnabled {
+		result.ChannelPointsPerVote = poll.ChannelPointsPerVote
+	}
+	if !poll.EndedAt.IsZero() {
+		result.EndedAt = &poll.EndedAt.Time
+	}
+
+	return result
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+
+	"github.com/arnokay/arnobot-shared/apperror"
+	"github.com/nicklaw5/helix/v2"
+
+	"github.com/arnokay/arnobot-twitch/internal/data"
+)
+
+func (s *TwitchService) PredictionCreate(ctx context.Context, arg data.PredictionCreate) (data.Prediction, error) {
+	client, err := s.userClient(ctx, arg.BroadcasterID)
+	if err != nil {
+		return data.Prediction{}, err
+	}
+
+	outcomes := make([]helix.PredictionChoiceParam, 0, len(arg.Outcomes))
+	for _, outcome := range arg.Outcomes {
+		outcomes = append(outcomes, helix.PredictionChoiceParam{Title: outcome})
+	}
+
+	res, err := client.CreatePrediction(&helix.CreatePredictionParams{
+		BroadcasterID:    arg.BroadcasterID,
+		Title:            arg.Title,
+		Outcomes:         outcomes,
+		PredictionWindow: arg.PredictionWindow,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot create prediction", "err", err, "arg", arg)
+		return data.Prediction{}, apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot create prediction", "status", res.StatusCode, "err_msg", res.ErrorMessage, "arg", arg)
+		return data.Prediction{}, responseErr(res.ResponseCommon)
+	}
+	if len(res.Data.Predictions) == 0 {
+		return data.Prediction{}, apperror.ErrExternal
+	}
+
+	return newPredictionFromHelix(res.Data.Predictions[0]), nil
+}
+
+func (s *TwitchService) PredictionLock(ctx context.Context, arg data.PredictionLock) (data.Prediction, error) {
+	return s.predictionEnd(ctx, helix.EndPredictionParams{
+		BroadcasterID: arg.BroadcasterID,
+		ID:            arg.ID,
+		Status:        "LOCKED",
+	})
+}
+
+func (s *TwitchService) PredictionResolve(ctx context.Context, arg data.PredictionResolve) (data.Prediction, error) {
+	if arg.WinningOutcomeID == "" {
+		return data.Prediction{}, apperror.New(apperror.CodeInvalidInput, "winning outcome is required", nil)
+	}
+
+	return s.predictionEnd(ctx, helix.EndPredictionParams{
+		BroadcasterID:    arg.BroadcasterID,
+		ID:               arg.ID,
+		Status:           "RESOLVED",
+		WinningOutcomeID: arg.WinningOutcomeID,
+	})
+}
+
+func (s *TwitchService) PredictionCancel(ctx context.Context, arg data.PredictionCancel) (data.Prediction, error) {
+	return s.predictionEnd(ctx, helix.EndPredictionParams{
+		BroadcasterID: arg.BroadcasterID,
+		ID:            arg.ID,
+		Status:        "CANCELED",
+	})
+}
+
+// predictionEnd locks, resolves or cancels prediction depending on status.
+func (s *TwitchService) predictionEnd(ctx context.Context, params helix.EndPredictionParams) (data.Prediction, error) {
+	client, err := s.userClient(ctx, params.BroadcasterID)
+	if err != nil {
+		return data.Prediction{}, err
+	}
+
+	res, err := client.EndPrediction(&params)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot end prediction", "err", err, "params", params)
+		return data.Prediction{}, apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot end prediction", "status", res.StatusCode, "err_msg", res.ErrorMessage, "params", params)
+		return data.Prediction{}, responseErr(res.ResponseCommon)
+	}
+	if len(res.Data.Predictions) == 0 {
+		return data.Prediction{}, apperror.ErrExternal
+	}
+
+	return newPredictionFromHelix(res.Data.Predictions[0]), nil
+}
+
+func newPredictionFromHelix(prediction helix.Prediction) data.Prediction {
+	result := data.Prediction{
+		ID:               prediction.ID,
+		BroadcasterID:    prediction.BroadcasterUserID,
+		Title:            prediction.Title,
+		WinningOutcomeID: prediction.WinningOutcomeID,
+		Outcomes:         data.NewPredictionOutcomesFromHelix(prediction.Outcomes),
+		PredictionWindow: prediction.PredictionWindow,
+		Status:           prediction.Status,
+		CreatedAt:        prediction.CreatedAt.Time,
+	}
+	if !prediction.LockedAt.IsZero() {
+		result.LockedAt = &prediction.LockedAt.Time
+	}
+	if !prediction.EndedAt.IsZero() {
+		result.EndedAt = &prediction.EndedAt.Time
+	}
+
+	return result
+}
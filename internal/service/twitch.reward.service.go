@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/arnokay/arnobot-shared/apperror"
+	"github.com/nicklaw5/helix/v2"
+
+	"github.com/arnokay/arnobot-twitch/internal/data"
+)
+
+// RewardRedemptionStatusUpdate fulfills or cancels redemption with the
+// broadcaster token. Only redemptions of rewards created by our client can be
+// updated.
+func (s *TwitchService) RewardRedemptionStatusUpdate(ctx context.Context, arg data.RewardRedemptionStatusUpdate) (bool, error) {
+	if arg.Status != data.RedemptionFulfilled && arg.Status != data.RedemptionCanceled {
+		return false, apperror.New(apperror.CodeInvalidInput, "status should be FULFILLED or CANCELED", nil)
+	}
+
+	client, err := s.userClient(ctx, arg.BroadcasterID)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := client.UpdateChannelCustomRewardsRedemptionStatus(&helix.UpdateChannelCustomRewardsRedemptionStatusParams{
+		ID:            arg.RedemptionID,
+		BroadcasterID: arg.BroadcasterID,
+		RewardID:      arg.RewardID,
+		Status:        string(arg.Status),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot update redemption status", "err", err, "arg", arg)
+		return false, apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot update redemption status", "status", res.StatusCode, "err_msg", res.ErrorMessage, "arg", arg)
+		return false, responseErr(res.ResponseCommon)
+	}
+
+	return true, nil
+}
+
+func (s *TwitchService) RewardCreate(ctx context.Context, arg data.RewardCreate) (data.Reward, error) {
+	client, err := s.userClient(ctx, arg.BroadcasterID)
+	if err != nil {
+		return data.Reward{}, err
+	}
+
+	res, err := client.CreateCustomReward(&helix.ChannelCustomRewardsParams{
+		BroadcasterID:                     arg.BroadcasterID,
+		Title:                             arg.Title,
+		Cost:                              arg.Cost,
+		Prompt:                            arg.Prompt,
+		IsEnabled:                         arg.IsEnabled,
+		BackgroundColor:                   arg.BackgroundColor,
+		IsUserInputRequired:               arg.IsUserInputRequired,
+		IsMaxPerStreamEnabled:             arg.MaxPerStream > 0,
+		MaxPerStream:                      arg.MaxPerStream,
+		IsMaxPerUserPerStreamEnabled:      arg.MaxPerUserPerStream > 0,
+		MaxPerUserPerStream:               arg.MaxPerUserPerStream,
+		IsGlobalCooldownEnabled:           arg.GlobalCooldownSeconds > 0,
+		GlobalCooldownSeconds:             arg.GlobalCooldownSeconds,
+		ShouldRedemptionsSkipRequestQueue: arg.SkipRequestQueue,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot create reward", "err", err, "arg", arg)
+		return data.Reward{}, apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot create reward", "status", res.StatusCode, "err_msg", res.ErrorMessage, "arg", arg)
+		return data.Reward{}, responseErr(res.ResponseCommon)
+	}
+	if len(res.Data.ChannelCustomRewards) == 0 {
+		return data.Reward{}, apperror.ErrExternal
+	}
+
+	return newRewardFromHelix(res.Data.ChannelCustomRewards[0]), nil
+}
+
+// RewardUpdate goes around helix client, because it always sends every field
+// and doesn't support pausing.
+func (s *TwitchService) RewardUpdate(ctx context.Context, arg data.RewardUpdate) (data.Reward, error) {
+	client, err := s.userClient(ctx, arg.BroadcasterID)
+	if err != nil {
+		return data.Reward{}, err
+	}
+
+	body := map[string]any{}
+	if arg.Title != nil {
+		body["title"] = *arg.Title
+	}
+	if arg.Prompt != nil {
+		body["prompt"] = *arg.Prompt
+	}
+	if arg.Cost != nil {
+		body["cost"] = *arg.Cost
+	}
+	if arg.BackgroundColor != nil {
+		body["background_color"] = *arg.BackgroundColor
+	}
+	if arg.IsEnabled != nil {
+		body["is_enabled"] = *arg.IsEnabled
+	}
+	if arg.IsPaused != nil {
+		body["is_paused"] = *arg.IsPaused
+	}
+	if arg.IsUserInputRequired != nil {
+		body["is_user_input_required"] = *arg.IsUserInputRequired
+	}
+	if arg.MaxPerStream != nil {
+		body["is_max_per_stream_enabled"] = *arg.MaxPerStream > 0
+		if *arg.MaxPerStream > 0 {
+			body["max_per_stream"] = *arg.MaxPerStream
+		}
+	}
+	if arg.MaxPerUserPerStream != nil {
+		body["is_max_per_user_per_stream_enabled"] = *arg.MaxPerUserPerStream > 0
+		if *arg.MaxPerUserPerStream > 0 {
+			body["max_per_user_per_stream"] = *arg.MaxPerUserPerStream
+		}
+	}
+	if arg.GlobalCooldownSeconds != nil {
+		body["is_global_cooldown_enabled"] = *arg.GlobalCooldownSeconds > 0
+		if *arg.GlobalCooldownSeconds > 0 {
+			body["global_cooldown_seconds"] = *arg.GlobalCooldownSeconds
+		}
+	}
+	if arg.SkipRequestQueue != nil {
+		body["should_redemptions_skip_request_queue"] = *arg.SkipRequestQueue
+	}
+
+	var rewards helix.ManyChannelCustomRewards
+	res, err := s.helixManager.Request(
+		ctx,
+		client,
+		http.MethodPatch,
+		"/channel_points/custom_rewards",
+		url.Values{"broadcaster_id": {arg.BroadcasterID}, "id": {arg.ID}},
+		body,
+		&rewards,
+	)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot update reward", "err", err, "arg", arg)
+		return data.Reward{}, apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot update reward", "status", res.StatusCode, "err_msg", res.ErrorMessage, "arg", arg)
+		return data.Reward{}, responseErr(res)
+	}
+	if len(rewards.ChannelCustomRewards) == 0 {
+		return data.Reward{}, apperror.ErrExternal
+	}
+
+	return newRewardFromHelix(rewards.ChannelCustomRewards[0]), nil
+}
+
+func (s *TwitchService) RewardDelete(ctx context.Context, arg data.RewardDelete) (bool, error) {
+	client, err := s.userClient(ctx, arg.BroadcasterID)
+	if err != nil {
+		return false, err
+	}
+
+	res, err := client.DeleteCustomRewards(&helix.DeleteCustomRewardsParams{
+		BroadcasterID: arg.BroadcasterID,
+		ID:            arg.ID,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot delete reward", "err", err, "arg", arg)
+		return false, apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot delete reward", "status", res.StatusCode, "err_msg", res.ErrorMessage, "arg", arg)
+		return false, responseErr(res.ResponseCommon)
+	}
+
+	return true, nil
+}
+
+func (s *TwitchService) RewardsGet(ctx context.Context, arg data.RewardsGet) ([]data.Reward, error) {
+	client, err := s.userClient(ctx, arg.BroadcasterID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.GetCustomRewards(&helix.GetCustomRewardsParams{
+		BroadcasterID:         arg.BroadcasterID,
+		OnlyManageableRewards: arg.OnlyManageable,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot get rewards", "err", err, "arg", arg)
+		return nil, apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot get rewards", "status", res.StatusCode, "err_msg", res.ErrorMessage, "arg", arg)
+		return nil, responseErr(res.ResponseCommon)
+	}
+
+	rewards := make([]data.Reward, 0, len(res.Data.ChannelCustomRewards))
+	for _, reward := range res.Data.ChannelCustomRewards {
+		rewards = append(rewards, newRewardFromHelix(reward))
+	}
+
+	return rewards, nil
+}
+
+func newRewardFromHelix(reward helix.ChannelCustomReward) data.Reward {
+	result := data.Reward{
+		ID:                  reward.ID,
+		BroadcasterID:       reward.BroadcasterID,
+		Title:               reward.Title,
+		Prompt:              reward.Prompt,
+		Cost:                reward.Cost,
+		BackgroundColor:     reward.BackgroundColor,
+		IsEnabled:           reward.IsEnabled,
+		IsPaused:            reward.IsPaused,
+		IsInStock:           reward.IsInStock,
+		IsUserInputRequired: reward.IsUserInputRequired,
+		SkipRequestQueue:    reward.ShouldRedemptionsSkipRequestQueue,
+	}
+	if reward.MaxPerStreamSetting.IsEnabled {
+		result.MaxPerStream = reward.MaxPerStreamSetting.MaxPerStream
+	}
+	if reward.MaxPerUserPerStreamSetting.IsEnabled {
+		result.MaxPerUserPerStream = reward.MaxPerUserPerStreamSetting.MaxPerUserPerStream
+	}
+	if reward.GlobalCooldownSetting.IsEnabled {
+		result.GlobalCooldownSeconds = reward.GlobalCooldownSetting.GlobalCooldownSeconds
+	}
+
+	return result
+}
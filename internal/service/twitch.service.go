@@ -2,29 +2,55 @@ package service
 
 import (
 	"context"
-	
+	"errors"
+	"net/http"
 
 	"github.com/arnokay/arnobot-shared/apperror"
 	"github.com/arnokay/arnobot-shared/applog"
+	"github.com/arnokay/arnobot-shared/data"
+	"github.com/arnokay/arnobot-shared/platform"
+	sharedService "github.com/arnokay/arnobot-shared/service"
+	"github.com/nats-io/nats.go/jetstream"
 	"github.com/nicklaw5/helix/v2"
 )
 
 type TwitchService struct {
 	helixManager *HelixManager
+	authModule   *sharedService.AuthModule
+	cache        jetstream.KeyValue
 	logger       applog.Logger
 }
 
 func NewTwitchService(
 	helixManager *HelixManager,
+	authModule *sharedService.AuthModule,
+	cache jetstream.KeyValue,
 ) *TwitchService {
 	logger := applog.NewServiceLogger("twitch-service")
 
 	return &TwitchService{
 		helixManager: helixManager,
+		authModule:   authModule,
+		cache:        cache,
 		logger:       logger,
 	}
 }
 
+// userClient returns helix client authorized with user access token of the
+// twitch user (broadcaster or bot).
+func (s *TwitchService) userClient(ctx context.Context, twitchUserID string) (*helix.Client, error) {
+	provider, err := s.authModule.AuthProviderGet(ctx, data.AuthProviderGet{
+		ProviderUserID: &twitchUserID,
+		Provider:       platform.Twitch.String(),
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot get auth provider", "err", err, "twitchUserID", twitchUserID)
+		return nil, err
+	}
+
+	return s.helixManager.GetByProvider(ctx, *provider), nil
+}
+
 func (s *TwitchService) AppSendChannelMessage(
 	ctx context.Context,
 	botID string,
@@ -47,3 +73,61 @@ func (s *TwitchService) AppSendChannelMessage(
 
 	return nil
 }
+
+func (s *TwitchService) BotSendShoutout(
+	ctx context.Context,
+	botID string,
+	broadcasterID string,
+	toBroadcasterID string,
+) error {
+	client, err := s.userClient(ctx, botID)
+	if err != nil {
+		return err
+	}
+
+	res, err := client.SendShoutout(&helix.SendShoutoutParams{
+		FromBroadcasterID: broadcasterID,
+		ToBroadcasterID:   toBroadcasterID,
+		ModeratorID:       botID,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot send shoutout", "err", err, "broadcasterID", broadcasterID, "botID", botID, "toBroadcasterID", toBroadcasterID)
+		return apperror.ErrExternal
+	}
+	if res.StatusCode >= 400 {
+		s.logger.ErrorContext(ctx, "cannot send shoutout", "status", res.StatusCode, "err_msg", res.ErrorMessage, "broadcasterID", broadcasterID, "botID", botID, "toBroadcasterID", toBroadcasterID)
+		return responseErr(res.ResponseCommon)
+	}
+
+	return nil
+}
+
+// responseErr maps helix error response to app error, so core can tell
+// what went wrong.
+func responseErr(res helix.ResponseCommon) error {
+	switch res.StatusCode {
+	case http.StatusBadRequest:
+		return apperror.New(apperror.CodeInvalidInput, res.ErrorMessage, nil)
+	case http.StatusUnauthorized:
+		return apperror.New(apperror.CodeUnauthorized, res.ErrorMessage, nil)
+	case http.StatusForbidden:
+		return apperror.New(apperror.CodeForbidden, res.ErrorMessage, nil)
+	case http.StatusNotFound:
+		return apperror.New(apperror.CodeNotFound, res.ErrorMessage, nil)
+	default:
+		return apperror.New(apperror.CodeExternal, res.ErrorMessage, nil)
+	}
+}
+
+// hasErrCode reports whether any app error in the chain has code, services
+// wrap specific errors into generic ones.
+func hasErrCode(err error, code apperror.ErrorCode) bool {
+	for err != nil {
+		if appErr, ok := err.(apperror.AppError); ok && appErr.Code == code {
+			return true
+		}
+		err = errors.Unwrap(err)
+	}
+
+	return false
+}
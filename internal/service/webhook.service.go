@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"sync"
 
 	"github.com/arnokay/arnobot-shared/apperror"
@@ -11,66 +12,109 @@ import (
 	"github.com/nicklaw5/helix/v2"
 
 	"github.com/arnokay/arnobot-twitch/internal/config"
+	"github.com/arnokay/arnobot-twitch/internal/data"
 )
 
 type EventSubscriptionRequest struct {
-	EventType     string
-	BroadcasterID string
-	UserID        string
-	Version       string
+	EventType         string
+	BroadcasterID     string
+	FromBroadcasterID string
+	ToBroadcasterID   string
+	UserID            string
+	ModeratorID       string
+	Version           string
 }
 
 type SubscriptionResult struct {
 	EventType string
+	Required  bool
 	Error     error
 }
 
 type WebhookService struct {
 	helixManager  *HelixManager
 	twitchService *TwitchService
-	logger        applog.Logger
-	callbackURL   string
-	secret        string
+	// websocket is nil when webhook transport is used
+	websocket   *EventSubWebsocketService
+	logger      applog.Logger
+	callbackURL string
+	secret      string
 }
 
 func NewWebhookService(
 	helixManager *HelixManager,
 	twitchService *TwitchService,
+	websocket *EventSubWebsocketService,
 ) *WebhookService {
 	logger := applog.NewServiceLogger("webhook-service")
 
 	return &WebhookService{
 		helixManager:  helixManager,
 		twitchService: twitchService,
+		websocket:     websocket,
 		logger:        logger,
 		callbackURL:   config.Config.Webhooks.Callback,
 		secret:        config.Config.Webhooks.Secret,
 	}
 }
 
+// tokenUserID is the user whose token creates websocket subscription, it has
+// to be one of the users in the condition.
+func (req EventSubscriptionRequest) tokenUserID() string {
+	for _, id := range []string{req.UserID, req.ModeratorID, req.BroadcasterID, req.ToBroadcasterID, req.FromBroadcasterID} {
+		if id != "" {
+			return id
+		}
+	}
+
+	return ""
+}
+
 func (s *WebhookService) createEventSubscription(
 	ctx context.Context,
 	client *helix.Client,
 	req EventSubscriptionRequest,
 ) error {
 	condition := helix.EventSubCondition{
-		BroadcasterUserID: req.BroadcasterID,
-		UserID:            req.UserID,
+		BroadcasterUserID:     req.BroadcasterID,
+		FromBroadcasterUserID: req.FromBroadcasterID,
+		ToBroadcasterUserID:   req.ToBroadcasterID,
+		UserID:                req.UserID,
+		ModeratorUserID:       req.ModeratorID,
 	}
 
   if req.Version == "" {
     req.Version = "1"
   }
 
+	transport := helix.EventSubTransport{
+		Method:   "webhook",
+		Callback: s.callbackURL,
+		Secret:   s.secret,
+	}
+	if s.websocket != nil {
+		sessionID := s.websocket.SessionID()
+		if sessionID == "" {
+			return apperror.New(apperror.CodeExternal, "eventsub websocket session is not established", nil)
+		}
+		transport = helix.EventSubTransport{
+			Method:    "websocket",
+			SessionID: sessionID,
+		}
+
+		// websocket subscriptions cannot be created with app token
+		var err error
+		client, err = s.twitchService.userClient(ctx, req.tokenUserID())
+		if err != nil {
+			return err
+		}
+	}
+
 	subscription := &helix.EventSubSubscription{
 		Type:      req.EventType,
 		Version:   req.Version,
 		Condition: condition,
-		Transport: helix.EventSubTransport{
-			Method:   "webhook",
-			Callback: s.callbackURL,
-			Secret:   s.secret,
-		},
+		Transport: transport,
 	}
 
 	response, err := client.CreateEventSubSubscription(subscription)
@@ -79,7 +123,14 @@ func (s *WebhookService) createEventSubscription(
 	}
 
 	if response.StatusCode >= 400 {
-		return apperror.New(apperror.CodeExternal, fmt.Sprintf("subscription failed with status %d: %s", response.StatusCode, response.ErrorMessage), nil)
+		code := apperror.CodeExternal
+		switch response.StatusCode {
+		case http.StatusForbidden:
+			code = apperror.CodeForbidden
+		case http.StatusConflict:
+			code = apperror.CodeAlreadyExists
+		}
+		return apperror.New(code, fmt.Sprintf("subscription failed with status %d: %s", response.StatusCode, response.ErrorMessage), nil)
 	}
 
 	return nil
@@ -105,6 +156,40 @@ func (s *WebhookService) SubscribeChannelChatMessage(ctx context.Context, botID,
 	return nil
 }
 
+// SubscribeChannelChatNotification uses the same user:read:chat condition as
+// chat message subscription.
+func (s *WebhookService) SubscribeChannelChatNotification(ctx context.Context, botID, broadcasterID string) error {
+	return s.subscribe(ctx, "chat notification", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelChatNotification,
+		BroadcasterID: broadcasterID,
+		UserID:        botID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelChatMessageDelete(ctx context.Context, botID, broadcasterID string) error {
+	return s.subscribe(ctx, "chat message delete", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelChatMessageDelete,
+		BroadcasterID: broadcasterID,
+		UserID:        botID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelChatClear(ctx context.Context, botID, broadcasterID string) error {
+	return s.subscribe(ctx, "chat clear", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelChatClear,
+		BroadcasterID: broadcasterID,
+		UserID:        botID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelChatClearUserMessages(ctx context.Context, botID, broadcasterID string) error {
+	return s.subscribe(ctx, "chat clear user messages", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelChatClearUserMessages,
+		BroadcasterID: broadcasterID,
+		UserID:        botID,
+	})
+}
+
 func (s *WebhookService) SubscribeStreamOnline(ctx context.Context, broadcasterID string) error {
 	client := s.helixManager.GetApp(ctx)
 
@@ -141,9 +226,241 @@ func (s *WebhookService) SubscribeStreamOffline(ctx context.Context, broadcaster
 	return nil
 }
 
-func (s *WebhookService) Unsubscribe(ctx context.Context, subscriptionID string) error {
+func (s *WebhookService) SubscribeChannelUpdate(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel update", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelUpdate,
+		BroadcasterID: broadcasterID,
+		Version:       "2",
+	})
+}
+
+func (s *WebhookService) SubscribeChannelFollow(ctx context.Context, botID, broadcasterID string) error {
+	return s.subscribe(ctx, "channel follow", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelFollow,
+		BroadcasterID: broadcasterID,
+		ModeratorID:   botID,
+		Version:       "2",
+	})
+}
+
+func (s *WebhookService) SubscribeChannelSubscribe(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel subscribe", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelSubscription,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelSubscriptionMessage(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel subscription message", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelSubscriptionMessage,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelSubscriptionGift(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel subscription gift", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelSubscriptionGift,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelSubscriptionEnd(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel subscription end", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelSubscriptionEnd,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelCheer(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel cheer", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelCheer,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelRaidTo(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "incoming channel raid", EventSubscriptionRequest{
+		EventType:       helix.EventSubTypeChannelRaid,
+		ToBroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelRaidFrom(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "outgoing channel raid", EventSubscriptionRequest{
+		EventType:         helix.EventSubTypeChannelRaid,
+		FromBroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelPointsRedemptionAdd(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel points redemption add", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelPointsCustomRewardRedemptionAdd,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelPointsRedemptionUpdate(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel points redemption update", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelPointsCustomRewardRedemptionUpdate,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelPollBegin(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel poll begin", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelPollBegin,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelPollProgress(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel poll progress", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelPollProgress,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelPollEnd(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel poll end", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelPollEnd,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelPredictionBegin(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel prediction begin", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelPredictionBegin,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelPredictionProgress(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel prediction progress", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelPredictionProgress,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelPredictionLock(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel prediction lock", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelPredictionLock,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeChannelPredictionEnd(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel prediction end", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeChannelPredictionEnd,
+		BroadcasterID: broadcasterID,
+	})
+}
+
+// hype train v1 is deprecated by twitch, v2 payload is handled by the
+// webhook controller.
+func (s *WebhookService) SubscribeHypeTrainBegin(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "hype train begin", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeHypeTrainBegin,
+		BroadcasterID: broadcasterID,
+		Version:       "2",
+	})
+}
+
+func (s *WebhookService) SubscribeHypeTrainProgress(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "hype train progress", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeHypeTrainProgress,
+		BroadcasterID: broadcasterID,
+		Version:       "2",
+	})
+}
+
+func (s *WebhookService) SubscribeHypeTrainEnd(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "hype train end", EventSubscriptionRequest{
+		EventType:     helix.EventSubTypeHypeTrainEnd,
+		BroadcasterID: broadcasterID,
+		Version:       "2",
+	})
+}
+
+func (s *WebhookService) SubscribeChannelModerate(ctx context.Context, botID, broadcasterID string) error {
+	return s.subscribe(ctx, "channel moderate", EventSubscriptionRequest{
+		EventType:     "channel.moderate",
+		BroadcasterID: broadcasterID,
+		ModeratorID:   botID,
+		Version:       "2",
+	})
+}
+
+func (s *WebhookService) SubscribeChannelAdBreakBegin(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "channel ad break begin", EventSubscriptionRequest{
+		EventType:     "channel.ad_break.begin",
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeAutomodMessageHold(ctx context.Context, botID, broadcasterID string) error {
+	return s.subscribe(ctx, "automod message hold", EventSubscriptionRequest{
+		EventType:     "automod.message.hold",
+		BroadcasterID: broadcasterID,
+		ModeratorID:   botID,
+	})
+}
+
+func (s *WebhookService) SubscribeAutomodMessageUpdate(ctx context.Context, botID, broadcasterID string) error {
+	return s.subscribe(ctx, "automod message update", EventSubscriptionRequest{
+		EventType:     "automod.message.update",
+		BroadcasterID: broadcasterID,
+		ModeratorID:   botID,
+	})
+}
+
+func (s *WebhookService) SubscribeSharedChatBegin(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "shared chat begin", EventSubscriptionRequest{
+		EventType:     "channel.shared_chat.begin",
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeSharedChatUpdate(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "shared chat update", EventSubscriptionRequest{
+		EventType:     "channel.shared_chat.update",
+		BroadcasterID: broadcasterID,
+	})
+}
+
+func (s *WebhookService) SubscribeSharedChatEnd(ctx context.Context, broadcasterID string) error {
+	return s.subscribe(ctx, "shared chat end", EventSubscriptionRequest{
+		EventType:     "channel.shared_chat.end",
+		BroadcasterID: broadcasterID,
+	})
+}
+
+// subscribe creates subscription with app client, name is used for logs and
+// errors.
+func (s *WebhookService) subscribe(ctx context.Context, name string, req EventSubscriptionRequest) error {
 	client := s.helixManager.GetApp(ctx)
 
+	err := s.createEventSubscription(ctx, client, req)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "cannot create "+name+" subscription",
+			"err", err,
+			"eventType", req.EventType,
+			"broadcasterID", req.BroadcasterID,
+			"fromBroadcasterID", req.FromBroadcasterID,
+			"toBroadcasterID", req.ToBroadcasterID,
+			"userID", req.UserID,
+			"moderatorID", req.ModeratorID,
+		)
+		return apperror.New(apperror.CodeExternal, "failed to subscribe to "+name+" events", err)
+	}
+
+	return nil
+}
+
+func (s *WebhookService) Unsubscribe(ctx context.Context, subscriptionID string) error {
+	return s.removeSubscription(ctx, s.helixManager.GetApp(ctx), subscriptionID)
+}
+
+func (s *WebhookService) removeSubscription(ctx context.Context, client *helix.Client, subscriptionID string) error {
 	response, err := client.RemoveEventSubSubscription(subscriptionID)
 	if err != nil {
 		return apperror.New(apperror.CodeExternal, "failed to remove event subscription", err)
@@ -162,28 +479,84 @@ func (s *WebhookService) Unsubscribe(ctx context.Context, subscriptionID string)
 }
 
 func (s *WebhookService) UnsubscribeAllBot(ctx context.Context, botID, broadcasterID string) error {
-	client := s.helixManager.GetApp(ctx)
+	clients := []*helix.Client{s.helixManager.GetApp(ctx)}
+	if s.websocket != nil {
+		// websocket subscriptions are only visible to the token that created
+		// them
+		clients = nil
+		for _, userID := range []string{botID, broadcasterID} {
+			client, err := s.twitchService.userClient(ctx, userID)
+			if err != nil {
+				return err
+			}
+			clients = append(clients, client)
+			if botID == broadcasterID {
+				break
+			}
+		}
+	}
+
+	for _, client := range clients {
+		subscriptionIDs, err := s.getSubscriptionIDs(ctx, client, botID, broadcasterID)
+		if err != nil {
+			return apperror.New(apperror.CodeExternal, "failed to get subscription IDs", err)
+		}
+
+		if len(subscriptionIDs) == 0 {
+			s.logger.DebugContext(ctx, "no subscriptions to unsubscribe")
+			continue
+		}
+
+		err = s.unsubscribeAll(ctx, client, subscriptionIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *WebhookService) getSubscriptionIDs(ctx context.Context, client *helix.Client, botID, broadcasterID string) ([]string, error) {
+	inChannel := func(sub helix.EventSubSubscription) bool {
+		return data.GetConditionBroadcasterID(sub.Condition) == broadcasterID
+	}
+
+	if s.websocket != nil {
+		// client only sees subscriptions of its own token, user_id matches
+		// any user in the condition
+		return s.listSubscriptionIDs(ctx, client, broadcasterID, inChannel)
+	}
 
-	subscriptionIDs, err := s.getSubscriptionIDs(ctx, client, botID, broadcasterID)
+	// app token sees subscriptions of every bot, so only the ones with the
+	// bot are taken
+	subscriptionIDs, err := s.listSubscriptionIDs(ctx, client, botID, inChannel)
 	if err != nil {
-		return apperror.New(apperror.CodeExternal, "failed to get subscription IDs", err)
+		return nil, err
 	}
 
-	if len(subscriptionIDs) == 0 {
-		s.logger.DebugContext(ctx, "no subscriptions to unsubscribe")
-		return nil
+	// raid and stream subscriptions don't have bot in the condition
+	botless, err := s.listSubscriptionIDs(ctx, client, broadcasterID, func(sub helix.EventSubSubscription) bool {
+		return inChannel(sub) && sub.Condition.UserID == "" && sub.Condition.ModeratorUserID == ""
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return s.unsubscribeAll(ctx, subscriptionIDs)
+	return append(subscriptionIDs, botless...), nil
 }
 
-func (s *WebhookService) getSubscriptionIDs(ctx context.Context, client *helix.Client, botID, broadcasterID string) ([]string, error) {
+func (s *WebhookService) listSubscriptionIDs(
+	ctx context.Context,
+	client *helix.Client,
+	userID string,
+	filter func(sub helix.EventSubSubscription) bool,
+) ([]string, error) {
 	var subscriptionIDs []string
 	var cursor string
 
 	for {
 		subs, err := client.GetEventSubSubscriptions(&helix.EventSubSubscriptionsParams{
-			UserID: botID,
+			UserID: userID,
 			After:  cursor,
 		})
 		if err != nil {
@@ -199,7 +572,7 @@ func (s *WebhookService) getSubscriptionIDs(ctx context.Context, client *helix.C
 		}
 
 		for _, sub := range subs.Data.EventSubSubscriptions {
-			if sub.Condition.BroadcasterUserID == broadcasterID {
+			if filter(sub) {
 				subscriptionIDs = append(subscriptionIDs, sub.ID)
 			}
 		}
@@ -214,7 +587,7 @@ func (s *WebhookService) getSubscriptionIDs(ctx context.Context, client *helix.C
 	return subscriptionIDs, nil
 }
 
-func (s *WebhookService) unsubscribeAll(ctx context.Context, subscriptionIDs []string) error {
+func (s *WebhookService) unsubscribeAll(ctx context.Context, client *helix.Client, subscriptionIDs []string) error {
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(subscriptionIDs))
 
@@ -222,7 +595,7 @@ func (s *WebhookService) unsubscribeAll(ctx context.Context, subscriptionIDs []s
 		wg.Add(1)
 		go func(subscriptionID string) {
 			defer wg.Done()
-			if err := s.Unsubscribe(ctx, subscriptionID); err != nil {
+			if err := s.removeSubscription(ctx, client, subscriptionID); err != nil {
 				errChan <- apperror.New(apperror.CodeExternal, fmt.Sprintf("failed to unsubscribe %s", subscriptionID), err)
 			}
 		}(id)
@@ -244,32 +617,106 @@ func (s *WebhookService) unsubscribeAll(ctx context.Context, subscriptionIDs []s
 	return nil
 }
 
-func (s *WebhookService) SubscribeAll(ctx context.Context, botID string, broadcasterID string) error {
-	subscriptions := []struct {
-		name string
-		fn   func() error
-	}{
-		{"chat_message", func() error { return s.SubscribeChannelChatMessage(ctx, botID, broadcasterID) }},
-		{"stream_online", func() error { return s.SubscribeStreamOnline(ctx, broadcasterID) }},
-		{"stream_offline", func() error { return s.SubscribeStreamOffline(ctx, broadcasterID) }},
+type botSubscription struct {
+	name     string
+	required bool
+	fn       func() error
+}
+
+// botSubscriptions are all subscriptions of a single bot in the channel.
+func (s *WebhookService) botSubscriptions(ctx context.Context, botID string, broadcasterID string) []botSubscription {
+	// only subscriptions the bot had from the start are required, the rest
+	// needs scopes or moderator role that older grants may not have
+	return []botSubscription{
+		{"chat_message", true, func() error { return s.SubscribeChannelChatMessage(ctx, botID, broadcasterID) }},
+		{"chat_notification", false, func() error { return s.SubscribeChannelChatNotification(ctx, botID, broadcasterID) }},
+		{"chat_message_delete", false, func() error { return s.SubscribeChannelChatMessageDelete(ctx, botID, broadcasterID) }},
+		{"chat_clear", false, func() error { return s.SubscribeChannelChatClear(ctx, botID, broadcasterID) }},
+		{"chat_clear_user_messages", false, func() error { return s.SubscribeChannelChatClearUserMessages(ctx, botID, broadcasterID) }},
+		{"stream_online", true, func() error { return s.SubscribeStreamOnline(ctx, broadcasterID) }},
+		{"stream_offline", true, func() error { return s.SubscribeStreamOffline(ctx, broadcasterID) }},
+		{"channel_update", false, func() error { return s.SubscribeChannelUpdate(ctx, broadcasterID) }},
+		{"channel_follow", false, func() error { return s.SubscribeChannelFollow(ctx, botID, broadcasterID) }},
+		{"channel_subscribe", false, func() error { return s.SubscribeChannelSubscribe(ctx, broadcasterID) }},
+		{"channel_subscription_message", false, func() error { return s.SubscribeChannelSubscriptionMessage(ctx, broadcasterID) }},
+		{"channel_subscription_gift", false, func() error { return s.SubscribeChannelSubscriptionGift(ctx, broadcasterID) }},
+		{"channel_subscription_end", false, func() error { return s.SubscribeChannelSubscriptionEnd(ctx, broadcasterID) }},
+		{"channel_cheer", false, func() error { return s.SubscribeChannelCheer(ctx, broadcasterID) }},
+		{"channel_raid_to", false, func() error { return s.SubscribeChannelRaidTo(ctx, broadcasterID) }},
+		{"channel_raid_from", false, func() error { return s.SubscribeChannelRaidFrom(ctx, broadcasterID) }},
+		{"channel_points_redemption_add", false, func() error { return s.SubscribeChannelPointsRedemptionAdd(ctx, broadcasterID) }},
+		{"channel_points_redemption_update", false, func() error { return s.SubscribeChannelPointsRedemptionUpdate(ctx, broadcasterID) }},
+		{"channel_poll_begin", false, func() error { return s.SubscribeChannelPollBegin(ctx, broadcasterID) }},
+		{"channel_poll_progress", false, func() error { return s.SubscribeChannelPollProgress(ctx, broadcasterID) }},
+		{"channel_poll_end", false, func() error { return s.SubscribeChannelPollEnd(ctx, broadcasterID) }},
+		{"channel_prediction_begin", false, func() error { return s.SubscribeChannelPredictionBegin(ctx, broadcasterID) }},
+		{"channel_prediction_progress", false, func() error { return s.SubscribeChannelPredictionProgress(ctx, broadcasterID) }},
+		{"channel_prediction_lock", false, func() error { return s.SubscribeChannelPredictionLock(ctx, broadcasterID) }},
+		{"channel_prediction_end", false, func() error { return s.SubscribeChannelPredictionEnd(ctx, broadcasterID) }},
+		{"hype_train_begin", false, func() error { return s.SubscribeHypeTrainBegin(ctx, broadcasterID) }},
+		{"hype_train_progress", false, func() error { return s.SubscribeHypeTrainProgress(ctx, broadcasterID) }},
+		{"hype_train_end", false, func() error { return s.SubscribeHypeTrainEnd(ctx, broadcasterID) }},
+		{"channel_ad_break_begin", false, func() error { return s.SubscribeChannelAdBreakBegin(ctx, broadcasterID) }},
+		{"channel_moderate", false, func() error { return s.SubscribeChannelModerate(ctx, botID, broadcasterID) }},
+		{"automod_message_hold", false, func() error { return s.SubscribeAutomodMessageHold(ctx, botID, broadcasterID) }},
+		{"automod_message_update", false, func() error { return s.SubscribeAutomodMessageUpdate(ctx, botID, broadcasterID) }},
+		{"shared_chat_begin", false, func() error { return s.SubscribeSharedChatBegin(ctx, broadcasterID) }},
+		{"shared_chat_update", false, func() error { return s.SubscribeSharedChatUpdate(ctx, broadcasterID) }},
+		{"shared_chat_end", false, func() error { return s.SubscribeSharedChatEnd(ctx, broadcasterID) }},
+	}
+}
+
+// WebsocketCheckCapacity returns error when channels don't fit into single
+// eventsub websocket session, there is no limit for webhook transport.
+func (s *WebhookService) WebsocketCheckCapacity(channels int) error {
+	if s.websocket == nil {
+		return nil
 	}
 
+	maxChannels := eventSubWebsocketMaxSubscriptions / len(s.botSubscriptions(context.Background(), "", ""))
+	if channels > maxChannels {
+		return apperror.New(
+			apperror.CodeInvalidInput,
+			fmt.Sprintf("eventsub websocket session fits %d channels, %d requested, use webhook transport", maxChannels, channels),
+			nil,
+		)
+	}
+
+	return nil
+}
+
+func (s *WebhookService) SubscribeAll(ctx context.Context, botID string, broadcasterID string) error {
 	var results []SubscriptionResult
-	for _, sub := range subscriptions {
+	for _, sub := range s.botSubscriptions(ctx, botID, broadcasterID) {
 		err := sub.fn()
 		results = append(results, SubscriptionResult{
 			EventType: sub.name,
+			Required:  sub.required,
 			Error:     err,
 		})
 	}
 
 	var failedSubs []string
+	var skippedSubs []string
 	for _, result := range results {
-		if result.Error != nil {
+		switch {
+		case result.Error == nil:
+		case hasErrCode(result.Error, apperror.CodeAlreadyExists):
+		case !result.Required && hasErrCode(result.Error, apperror.CodeForbidden):
+			skippedSubs = append(skippedSubs, result.EventType)
+		default:
 			failedSubs = append(failedSubs, result.EventType)
 		}
 	}
 
+	if len(skippedSubs) > 0 {
+		s.logger.WarnContext(ctx, "skipped optional subscriptions",
+			"skipped_subscriptions", skippedSubs,
+			"botID", botID,
+			"broadcasterID", broadcasterID,
+		)
+	}
+
 	if len(failedSubs) > 0 {
 		s.logger.ErrorContext(ctx, "failed to subscribe to some events",
 			"failed_subscriptions", failedSubs,
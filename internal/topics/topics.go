@@ -0,0 +1,67 @@
+package topics
+
+// Topics that are specific to twitch and are not (yet) part of the shared
+// topics. Build them with the shared topics.TopicBuilder.
+const (
+	PlatformBroadcasterSubscriptionRevokedNotify = "eventsub.revocation.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterStreamOnlineNotify        = "stream.online.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterStreamOfflineNotify       = "stream.offline.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterChannelUpdateNotify       = "channel.update.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterFollowNotify              = "channel.follow.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterSubscribeNotify           = "channel.subscribe.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterSubscriptionMessageNotify = "channel.subscription.message.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterSubscriptionGiftNotify    = "channel.subscription.gift.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterSubscriptionEndNotify     = "channel.subscription.end.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterCheerNotify               = "channel.cheer.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterRaidNotify                = "channel.raid.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterRedemptionAddNotify       = "channel.reward-redemption.add.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterRedemptionUpdateNotify    = "channel.reward-redemption.update.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterPollBeginNotify           = "channel.poll.begin.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterPollProgressNotify        = "channel.poll.progress.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterPollEndNotify             = "channel.poll.end.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterPredictionBeginNotify     = "channel.prediction.begin.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterPredictionProgressNotify  = "channel.prediction.progress.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterPredictionLockNotify      = "channel.prediction.lock.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterPredictionEndNotify       = "channel.prediction.end.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterHypeTrainBeginNotify      = "channel.hype-train.begin.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterHypeTrainProgressNotify   = "channel.hype-train.progress.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterHypeTrainEndNotify        = "channel.hype-train.end.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterChatNotificationNotify    = "chat.notification.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterModerationActionNotify    = "channel.moderate.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterMessageDeleteNotify       = "chat.message.delete.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterChatClearNotify           = "chat.clear.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterChatClearUserNotify       = "chat.clear-user.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterAdBreakBeginNotify        = "channel.ad-break.begin.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterAutomodHoldNotify         = "automod.message.hold.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterAutomodUpdateNotify       = "automod.message.update.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterSharedChatBeginNotify     = "chat.shared.begin.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterSharedChatUpdateNotify    = "chat.shared.update.notify.{platform}.{broadcasterID}"
+	PlatformBroadcasterSharedChatEndNotify       = "chat.shared.end.notify.{platform}.{broadcasterID}"
+)
+
+const (
+	PlatformBroadcasterShoutoutSend                 = "channel.shoutout.send.{platform}.{broadcasterID}"
+	PlatformBroadcasterRewardRedemptionStatusUpdate = "channel.reward-redemption.status.{platform}.{broadcasterID}"
+	PlatformBroadcasterRewardCreate                 = "channel.reward.create.{platform}.{broadcasterID}"
+	PlatformBroadcasterRewardUpdate                 = "channel.reward.update.{platform}.{broadcasterID}"
+	PlatformBroadcasterRewardDelete                 = "channel.reward.delete.{platform}.{broadcasterID}"
+	PlatformBroadcasterRewardList                   = "channel.reward.list.{platform}.{broadcasterID}"
+	PlatformBroadcasterPollCreate                   = "channel.poll.create.{platform}.{broadcasterID}"
+	PlatformBroadcasterPollEnd                      = "channel.poll.end.{platform}.{broadcasterID}"
+	PlatformBroadcasterPredictionCreate             = "channel.prediction.create.{platform}.{broadcasterID}"
+	PlatformBroadcasterPredictionLock               = "channel.prediction.lock.{platform}.{broadcasterID}"
+	PlatformBroadcasterPredictionResolve            = "channel.prediction.resolve.{platform}.{broadcasterID}"
+	PlatformBroadcasterPredictionCancel             = "channel.prediction.cancel.{platform}.{broadcasterID}"
+	PlatformBroadcasterAdStart                      = "channel.ad.start.{platform}.{broadcasterID}"
+	PlatformBroadcasterAdScheduleGet                = "channel.ad.schedule.{platform}.{broadcasterID}"
+	PlatformBroadcasterAdSnooze                     = "channel.ad.snooze.{platform}.{broadcasterID}"
+	PlatformBroadcasterAutomodMessageModerate       = "automod.message.moderate.{platform}.{broadcasterID}"
+)
+
+// Admin topics are not scoped to a broadcaster.
+const (
+	PlatformDeadLetterList   = "admin.dlq.list.{platform}"
+	PlatformDeadLetterGet    = "admin.dlq.get.{platform}"
+	PlatformDeadLetterReplay = "admin.dlq.replay.{platform}"
+	PlatformDeadLetterPurge  = "admin.dlq.purge.{platform}"
+)